@@ -2,14 +2,17 @@
 package run
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/qonto/postgresql-partition-manager/internal/infra/config"
 	"github.com/qonto/postgresql-partition-manager/internal/infra/logger"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
 	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
 	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
 	"github.com/spf13/cobra"
@@ -24,8 +27,22 @@ const (
 	PartitionsCheckFailedExitCode        = 5
 	PartitionsCleanupFailedExitCode      = 6
 	InvalidDateExitCode                  = 7
+	PartitionsProtectionFailedExitCode   = 8
+	PartitionsIndexesFailedExitCode      = 9
+	PartitionsRestoreFailedExitCode      = 10
+	PartitionsReattachFailedExitCode     = 11
+	PartitionsHoldFailedExitCode         = 12
+	PartitionsRepairFailedExitCode       = 13
+	PartitionsRenameFailedExitCode       = 14
+	PartitionsSplitFailedExitCode        = 15
+	PartitionsMergeFailedExitCode        = 16
+	PartitionsExchangeFailedExitCode     = 17
 )
 
+const defaultHoldDays = 7
+
+const defaultBatchSize = 10000
+
 var ErrUnsupportedPostgreSQLVersion = errors.New("unsupported PostgreSQL version")
 
 func RunCmd() *cobra.Command {
@@ -41,20 +58,35 @@ func RunCmd() *cobra.Command {
 	runCmd.AddCommand(AllCmd)
 	runCmd.AddCommand(CheckCmd)
 	runCmd.AddCommand(ProvisioningCmd)
-	runCmd.AddCommand(CleanupCmd)
+	runCmd.AddCommand(CleanupCmd())
+	runCmd.AddCommand(ProtectCmd)
+	runCmd.AddCommand(IndexesCmd)
+	runCmd.AddCommand(CreateIndexCmd())
+	runCmd.AddCommand(ReindexCmd)
+	runCmd.AddCommand(UnlockCmd())
+	runCmd.AddCommand(RestoreCmd())
+	runCmd.AddCommand(ReattachCmd())
+	runCmd.AddCommand(HoldCmd())
+	runCmd.AddCommand(RepairCmd())
+	runCmd.AddCommand(RenameCmd())
+	runCmd.AddCommand(SplitCmd())
+	runCmd.AddCommand(MergeCmd())
+	runCmd.AddCommand(ExchangeCmd())
 
 	return runCmd
 }
 
 var AllCmd = &cobra.Command{
 	Use:   "all",
-	Short: "Perform partitions provisioning, cleanup, and check",
-	Long:  "Perform partitions provisioning, cleanup, and check. Default partitions are not supported.",
+	Short: "Perform partitions provisioning, cleanup, index management, protection, and check",
+	Long:  "Perform partitions provisioning, cleanup, index management, protection, and check. Default partitions are not supported.",
 	Run: func(cmd *cobra.Command, args []string) {
 		client := initCmd()
 
 		provisioningCmd(client)
 		cleanupCmd(client)
+		indexesCmd(client)
+		protectCmd(client)
 		checkCmd(client)
 	},
 }
@@ -69,14 +101,23 @@ var CheckCmd = &cobra.Command{
 	},
 }
 
-var CleanupCmd = &cobra.Command{
-	Use:   "cleanup",
-	Short: "Remove outdated partitions",
-	Long:  "Remove outdated partitions",
-	Run: func(cmd *cobra.Command, args []string) {
-		client := initCmd()
-		cleanupCmd(client)
-	},
+func CleanupCmd() *cobra.Command {
+	var force bool
+
+	cleanupCommand := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Remove outdated partitions",
+		Long:  "Remove outdated partitions. Partitions refused by the configured safeguards are kept unless --force is set.",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := initCmd()
+			client.SetForce(force)
+			cleanupCmd(client)
+		},
+	}
+
+	cleanupCommand.Flags().BoolVarP(&force, "force", "", false, "Remove partitions refused by the configured safeguards")
+
+	return cleanupCommand
 }
 
 var ProvisioningCmd = &cobra.Command{
@@ -89,6 +130,398 @@ var ProvisioningCmd = &cobra.Command{
 	},
 }
 
+var ProtectCmd = &cobra.Command{
+	Use:   "protect",
+	Short: "Make closed partitions read-only",
+	Long:  "Make partitions older than the readOnlyAfter setting read-only by attaching guard triggers",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := initCmd()
+		protectCmd(client)
+	},
+}
+
+var IndexesCmd = &cobra.Command{
+	Use:   "indexes",
+	Short: "Create and drop partition indexes according to index rules",
+	Long:  "Create indexes on partitions entering the age stage of an index rule and drop them from partitions leaving it",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := initCmd()
+		indexesCmd(client)
+	},
+}
+
+var ReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Repair invalid or missing partition indexes",
+	Long:  "Rebuild invalid partition indexes and create missing ones concurrently, then attach them to the parent table indexes",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := initCmd()
+
+		if err := client.RepairIndexes(); err != nil {
+			os.Exit(PartitionsIndexesFailedExitCode)
+		}
+	},
+}
+
+func CreateIndexCmd() *cobra.Command {
+	var table, index, definition string
+
+	createIndexCmd := &cobra.Command{
+		Use:   "create-index",
+		Short: "Create an index on a partitioned table without long locks",
+		Long:  "Create an index on the parent table only, build it concurrently on each partition, then attach each partition index. Can be resumed after an interruption.",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := initCmd()
+
+			if err := client.RolloutIndex(table, index, definition); err != nil {
+				os.Exit(PartitionsIndexesFailedExitCode)
+			}
+		},
+	}
+
+	createIndexCmd.Flags().StringVarP(&table, "table", "t", "", "Partition configuration name or managed table")
+	createIndexCmd.Flags().StringVarP(&index, "name", "n", "", "Index name on the parent table")
+	createIndexCmd.Flags().StringVarP(&definition, "definition", "", "", "Index definition following the table name (e.g. \"USING btree (customer_id)\")")
+	_ = createIndexCmd.MarkFlagRequired("table")
+	_ = createIndexCmd.MarkFlagRequired("name")
+	_ = createIndexCmd.MarkFlagRequired("definition")
+
+	return createIndexCmd
+}
+
+func UnlockCmd() *cobra.Command {
+	var table, partitionName string
+
+	unlockCmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Remove the read-only protection of a partition",
+		Long:  "Remove the read-only protection of a partition for sanctioned corrections. The protection is restored by the next protect operation.",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := initCmd()
+
+			if err := client.UnlockPartition(table, partitionName); err != nil {
+				os.Exit(PartitionsProtectionFailedExitCode)
+			}
+		},
+	}
+
+	unlockCmd.Flags().StringVarP(&table, "table", "t", "", "Partition configuration name or managed table")
+	unlockCmd.Flags().StringVarP(&partitionName, "partition", "p", "", "Partition to unlock")
+	_ = unlockCmd.MarkFlagRequired("table")
+	_ = unlockCmd.MarkFlagRequired("partition")
+
+	return unlockCmd
+}
+
+func RestoreCmd() *cobra.Command {
+	var table, from, to string
+
+	var holdDays int
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore archived partitions",
+		Long:  "Recreate archived partitions overlapping a date range from their archive, and attach them to the parent table. Restored partitions are held, so cleanup does not remove them during the hold period.",
+		Run: func(cmd *cobra.Command, args []string) {
+			fromDate, err := time.Parse(time.DateOnly, from)
+			if err != nil {
+				fmt.Println("ERROR: Could not parse --from date", "error", err)
+				os.Exit(InvalidDateExitCode)
+			}
+
+			toDate, err := time.Parse(time.DateOnly, to)
+			if err != nil {
+				fmt.Println("ERROR: Could not parse --to date", "error", err)
+				os.Exit(InvalidDateExitCode)
+			}
+
+			client := initCmd()
+
+			if err := client.RestorePartitions(table, fromDate, toDate, holdDays); err != nil {
+				os.Exit(PartitionsRestoreFailedExitCode)
+			}
+		},
+	}
+
+	restoreCmd.Flags().StringVarP(&table, "table", "t", "", "Partition configuration name or managed table")
+	restoreCmd.Flags().StringVarP(&from, "from", "", "", "First date of the range to restore (YYYY-MM-DD)")
+	restoreCmd.Flags().StringVarP(&to, "to", "", "", "Last date of the range to restore (YYYY-MM-DD)")
+	restoreCmd.Flags().IntVarP(&holdDays, "hold-days", "", defaultHoldDays, "Number of days restored partitions are kept by cleanup")
+	_ = restoreCmd.MarkFlagRequired("table")
+	_ = restoreCmd.MarkFlagRequired("from")
+	_ = restoreCmd.MarkFlagRequired("to")
+
+	return restoreCmd
+}
+
+func ReattachCmd() *cobra.Command {
+	var table, partitionName string
+
+	var holdDays int
+
+	reattachCmd := &cobra.Command{
+		Use:   "reattach",
+		Short: "Attach a partition detached by cleanup again",
+		Long:  "Attach a partition detached by cleanup again during its grace period, under its original name and schema. The partition is held, so cleanup does not detach it again during the hold period.",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := initCmd()
+
+			if err := client.ReattachPartition(table, partitionName, holdDays); err != nil {
+				os.Exit(PartitionsReattachFailedExitCode)
+			}
+		},
+	}
+
+	reattachCmd.Flags().StringVarP(&table, "table", "t", "", "Partition configuration name or managed table")
+	reattachCmd.Flags().StringVarP(&partitionName, "partition", "p", "", "Partition to reattach, by original or current name")
+	reattachCmd.Flags().IntVarP(&holdDays, "hold-days", "", defaultHoldDays, "Number of days the reattached partition is kept by cleanup")
+	_ = reattachCmd.MarkFlagRequired("table")
+	_ = reattachCmd.MarkFlagRequired("partition")
+
+	return reattachCmd
+}
+
+func HoldCmd() *cobra.Command {
+	var table, partitionName, until, reason string
+
+	holdCmd := &cobra.Command{
+		Use:   "hold",
+		Short: "Keep a partition past its retention",
+		Long:  "Record a hold in the partition comment, so cleanup keeps the partition until the given date. A date in the past releases the hold.",
+		Run: func(cmd *cobra.Command, args []string) {
+			untilDate, err := time.Parse(time.DateOnly, until)
+			if err != nil {
+				fmt.Println("ERROR: Could not parse --until date", "error", err)
+				os.Exit(InvalidDateExitCode)
+			}
+
+			client := initCmd()
+
+			if err := client.HoldPartition(table, partitionName, untilDate, reason); err != nil {
+				os.Exit(PartitionsHoldFailedExitCode)
+			}
+		},
+	}
+
+	holdCmd.Flags().StringVarP(&table, "table", "t", "", "Partition configuration name or managed table")
+	holdCmd.Flags().StringVarP(&partitionName, "partition", "p", "", "Partition to hold")
+	holdCmd.Flags().StringVarP(&until, "until", "", "", "Date until which the partition is kept (YYYY-MM-DD)")
+	holdCmd.Flags().StringVarP(&reason, "reason", "", "", "Why the partition is held, reported by cleanup")
+	_ = holdCmd.MarkFlagRequired("table")
+	_ = holdCmd.MarkFlagRequired("partition")
+	_ = holdCmd.MarkFlagRequired("until")
+
+	return holdCmd
+}
+
+func RepairCmd() *cobra.Command {
+	var yes bool
+
+	repairCmd := &cobra.Command{
+		Use:   "repair",
+		Short: "Fill gaps between existing partitions",
+		Long:  "List the gaps between the existing partitions of each partition set and the partitions filling them, then create these partitions once confirmed. Rows of the default partition within the created partitions are moved to them.",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := initCmd()
+
+			repairs, err := client.FindGapRepairs()
+			if err != nil {
+				fmt.Println("ERROR: Could not look for gaps", "error", err)
+				os.Exit(PartitionsRepairFailedExitCode)
+			}
+
+			if len(repairs) == 0 {
+				fmt.Println("No gap found")
+
+				return
+			}
+
+			fmt.Println("Partitions to create:")
+
+			for _, repair := range repairs {
+				fmt.Printf("  %s: %s %s\n", repair.Name, repair.Partition.QualifiedName(), partition.Bounds(repair.Partition.LowerBound, repair.Partition.UpperBound))
+			}
+
+			if !yes && !confirm(fmt.Sprintf("Create %d partitions?", len(repairs))) {
+				fmt.Println("Repair aborted")
+
+				return
+			}
+
+			if err := client.RepairGaps(repairs); err != nil {
+				os.Exit(PartitionsRepairFailedExitCode)
+			}
+		},
+	}
+
+	repairCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Create the partitions without confirmation")
+
+	return repairCmd
+}
+
+func RenameCmd() *cobra.Command {
+	var table string
+
+	var dryRun bool
+
+	renameCmd := &cobra.Command{
+		Use:   "rename",
+		Short: "Rename partitions to the naming convention",
+		Long:  "Rename attached partitions to the name computed from their bounds, one at a time. Partitions whose canonical name collides with another table or exceeds 63 bytes are not renamed.",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := initCmd()
+
+			renames, err := client.PlanRenames(table)
+			if err != nil {
+				fmt.Println("ERROR: Could not list partitions to rename", "error", err)
+				os.Exit(PartitionsRenameFailedExitCode)
+			}
+
+			if len(renames) == 0 {
+				fmt.Println("All partitions follow the naming convention")
+
+				return
+			}
+
+			fmt.Println("Partitions to rename:")
+
+			for _, rename := range renames {
+				if rename.Err != nil {
+					fmt.Printf("  %s: %s -> %s (skipped: %s)\n", rename.Name, rename.Partition.QualifiedName(), rename.NewName, rename.Err)
+				} else {
+					fmt.Printf("  %s: %s -> %s\n", rename.Name, rename.Partition.QualifiedName(), rename.NewName)
+				}
+			}
+
+			if dryRun {
+				return
+			}
+
+			if err := client.RenamePartitions(renames); err != nil {
+				os.Exit(PartitionsRenameFailedExitCode)
+			}
+		},
+	}
+
+	renameCmd.Flags().StringVarP(&table, "table", "t", "", "Partition configuration name or managed table, all partition sets when not set")
+	renameCmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "List the partitions to rename without renaming them")
+
+	return renameCmd
+}
+
+func SplitCmd() *cobra.Command {
+	var table, partitionName, into string
+
+	var batchSize int
+
+	var keep bool
+
+	splitCmd := &cobra.Command{
+		Use:   "split",
+		Short: "Split a partition into finer partitions",
+		Long:  "Split a partition into partitions of a finer interval. The partition is set read-only, its rows are copied to the new partitions in batches, then the new partitions replace it in a single transaction. The original partition is dropped unless --keep is set.",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := initCmd()
+
+			if err := client.SplitPartition(table, partitionName, partition.Interval(into), batchSize, keep); err != nil {
+				os.Exit(PartitionsSplitFailedExitCode)
+			}
+		},
+	}
+
+	splitCmd.Flags().StringVarP(&table, "table", "t", "", "Partition configuration name or managed table")
+	splitCmd.Flags().StringVarP(&partitionName, "partition", "p", "", "Partition to split")
+	splitCmd.Flags().StringVarP(&into, "into", "", "", "Interval of the new partitions (daily, weekly, monthly, quarterly)")
+	splitCmd.Flags().IntVarP(&batchSize, "batch-size", "", defaultBatchSize, "Maximum number of rows copied per batch")
+	splitCmd.Flags().BoolVarP(&keep, "keep", "", false, "Keep the original partition as a standalone table instead of dropping it")
+	_ = splitCmd.MarkFlagRequired("table")
+	_ = splitCmd.MarkFlagRequired("partition")
+	_ = splitCmd.MarkFlagRequired("into")
+
+	return splitCmd
+}
+
+func MergeCmd() *cobra.Command {
+	var table, date, into string
+
+	var batchSize int
+
+	var keep bool
+
+	mergeCmd := &cobra.Command{
+		Use:   "merge",
+		Short: "Merge adjacent partitions into one partition",
+		Long:  "Merge the partitions within the bounds of the coarser interval partition containing a date into a single partition. The partitions are set read-only, their rows are copied to the merged partition in batches, then the merged partition replaces them in a single transaction. The original partitions are dropped unless --keep is set.",
+		Run: func(cmd *cobra.Command, args []string) {
+			at, err := time.Parse(time.DateOnly, date)
+			if err != nil {
+				fmt.Println("ERROR: Could not parse --date date", "error", err)
+				os.Exit(InvalidDateExitCode)
+			}
+
+			client := initCmd()
+
+			if err := client.MergePartitions(table, at, partition.Interval(into), batchSize, keep); err != nil {
+				os.Exit(PartitionsMergeFailedExitCode)
+			}
+		},
+	}
+
+	mergeCmd.Flags().StringVarP(&table, "table", "t", "", "Partition configuration name or managed table")
+	mergeCmd.Flags().StringVarP(&date, "date", "", "", "Date within the merged partition (YYYY-MM-DD)")
+	mergeCmd.Flags().StringVarP(&into, "into", "", "", "Interval of the merged partition (weekly, monthly, quarterly, yearly)")
+	mergeCmd.Flags().IntVarP(&batchSize, "batch-size", "", defaultBatchSize, "Maximum number of rows copied per batch")
+	mergeCmd.Flags().BoolVarP(&keep, "keep", "", false, "Keep the original partitions as standalone tables instead of dropping them")
+	_ = mergeCmd.MarkFlagRequired("table")
+	_ = mergeCmd.MarkFlagRequired("date")
+	_ = mergeCmd.MarkFlagRequired("into")
+
+	return mergeCmd
+}
+
+func ExchangeCmd() *cobra.Command {
+	var table, partitionName, with string
+
+	var keep bool
+
+	exchangeCmd := &cobra.Command{
+		Use:   "exchange",
+		Short: "Replace a partition by a prepared table",
+		Long:  "Replace a partition by a prepared table with the same bounds. The table must have the columns, check constraints and indexes of the parent table. The table replaces the partition in a single transaction, without scanning it under lock. The original partition is dropped and the table renamed after it, unless --keep is set or the partition is held.",
+		Run: func(cmd *cobra.Command, args []string) {
+			client := initCmd()
+
+			if err := client.ExchangePartition(table, partitionName, with, keep); err != nil {
+				os.Exit(PartitionsExchangeFailedExitCode)
+			}
+		},
+	}
+
+	exchangeCmd.Flags().StringVarP(&table, "table", "t", "", "Partition configuration name or managed table")
+	exchangeCmd.Flags().StringVarP(&partitionName, "partition", "p", "", "Partition to replace")
+	exchangeCmd.Flags().StringVarP(&with, "with", "", "", "Prepared table replacing the partition, in the schema of the parent table")
+	exchangeCmd.Flags().BoolVarP(&keep, "keep", "", false, "Keep the original partition as a standalone table instead of dropping it")
+	_ = exchangeCmd.MarkFlagRequired("table")
+	_ = exchangeCmd.MarkFlagRequired("partition")
+	_ = exchangeCmd.MarkFlagRequired("with")
+
+	return exchangeCmd
+}
+
+// confirm asks a yes/no question on the standard input, answering no by default
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
 func initCmd() *ppm.PPM {
 	var config config.Config
 
@@ -162,3 +595,15 @@ func provisioningCmd(client *ppm.PPM) {
 		os.Exit(PartitionsProvisioningFailedExitCode)
 	}
 }
+
+func protectCmd(client *ppm.PPM) {
+	if err := client.ProtectPartitions(); err != nil {
+		os.Exit(PartitionsProtectionFailedExitCode)
+	}
+}
+
+func indexesCmd(client *ppm.PPM) {
+	if err := client.ManageIndexes(); err != nil {
+		os.Exit(PartitionsIndexesFailedExitCode)
+	}
+}
@@ -0,0 +1,47 @@
+// Package archive provides storage backends and file formats used to archive partitions
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+var (
+	ErrUnsupportedStorage     = errors.New("unsupported archive storage")
+	ErrUnsupportedCompression = errors.New("unsupported archive compression")
+)
+
+// File is a file being written to a storage
+type File interface {
+	io.WriteCloser
+	// Abort discards the file without making it visible, an existing file of the same name is left untouched
+	Abort() error
+}
+
+// Storage stores archive files
+type Storage interface {
+	// Create returns a writer for a new file, the file is only visible once the writer is successfully closed
+	Create(name string) (File, error)
+	Open(name string) (io.ReadCloser, error)
+	Exists(name string) (bool, error)
+	// List returns the names of the stored files
+	List() ([]string, error)
+}
+
+// NewStorage returns the storage backend matching the destination.
+// Destinations are local directories, either as a path or a file:// URL.
+func NewStorage(destination string) (Storage, error) {
+	location, err := url.Parse(destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive destination: %w", err)
+	}
+
+	switch location.Scheme {
+	case "", "file":
+		return NewLocalStorage(location.Path), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedStorage, location.Scheme)
+	}
+}
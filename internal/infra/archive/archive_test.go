@@ -0,0 +1,161 @@
+package archive_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/archive"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/stretchr/testify/assert"
+)
+
+const content = "id,created_at\n1,2024-01-01\n2,2024-01-01\n"
+
+func writeArchive(t *testing.T, storage archive.Storage, compression partition.ArchiveCompression) archive.Manifest {
+	t.Helper()
+
+	manifest := archive.Manifest{
+		Schema:      "public",
+		Table:       "my_table_2024_01_01",
+		File:        archive.FileName("public", "my_table_2024_01_01", partition.CSV, compression),
+		Format:      partition.CSV,
+		Compression: compression,
+		Rows:        2,
+	}
+
+	writer, err := archive.NewWriter(storage, manifest.File, compression)
+	assert.Nil(t, err, "NewWriter should succeed")
+
+	_, err = io.WriteString(writer, content)
+	assert.Nil(t, err, "Write should succeed")
+	assert.Nil(t, writer.Close(), "Close should succeed")
+
+	manifest.Checksum = writer.Checksum()
+
+	return manifest
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	testCases := []struct {
+		compression partition.ArchiveCompression
+		file        string
+	}{
+		{partition.NoCompression, "public.my_table_2024_01_01.csv"},
+		{partition.Gzip, "public.my_table_2024_01_01.csv.gz"},
+		{partition.Zstd, "public.my_table_2024_01_01.csv.zst"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.compression), func(t *testing.T) {
+			storage, err := archive.NewStorage("file://" + t.TempDir())
+			assert.Nil(t, err, "NewStorage should succeed")
+
+			manifest := writeArchive(t, storage, tc.compression)
+			assert.Equal(t, tc.file, manifest.File)
+
+			assert.Nil(t, archive.WriteManifest(storage, manifest), "WriteManifest should succeed")
+
+			read, err := archive.ReadManifest(storage, manifest.Schema, manifest.Table)
+			assert.Nil(t, err, "ReadManifest should succeed")
+			assert.Equal(t, manifest, read)
+
+			assert.Nil(t, archive.Verify(storage, read), "Verify should succeed")
+
+			file, err := storage.Open(manifest.File)
+			assert.Nil(t, err, "Open should succeed")
+
+			defer file.Close()
+
+			decompressor, err := archive.NewDecompressor(file, tc.compression)
+			assert.Nil(t, err, "NewDecompressor should succeed")
+
+			data, err := io.ReadAll(decompressor)
+			assert.Nil(t, err, "Decompression should succeed")
+			assert.Equal(t, content, string(data))
+		})
+	}
+}
+
+func TestVerifyCorruptedArchive(t *testing.T) {
+	directory := t.TempDir()
+	storage := archive.NewLocalStorage(directory)
+
+	manifest := writeArchive(t, storage, partition.Gzip)
+
+	err := os.WriteFile(filepath.Join(directory, manifest.File), []byte("corrupted"), 0o600)
+	assert.Nil(t, err, "WriteFile should succeed")
+
+	assert.Error(t, archive.Verify(storage, manifest), "Verify should fail on corrupted archive")
+
+	manifest = writeArchive(t, storage, partition.NoCompression)
+	manifest.Checksum = "invalid"
+
+	assert.ErrorIs(t, archive.Verify(storage, manifest), archive.ErrChecksumMismatch)
+}
+
+func TestLocalStorageExists(t *testing.T) {
+	storage := archive.NewLocalStorage(t.TempDir())
+
+	exists, err := storage.Exists("missing")
+	assert.Nil(t, err, "Exists should succeed")
+	assert.False(t, exists)
+
+	writer, err := storage.Create("file")
+	assert.Nil(t, err, "Create should succeed")
+
+	exists, _ = storage.Exists("file")
+	assert.False(t, exists, "File should not be visible before close")
+
+	assert.Nil(t, writer.Close(), "Close should succeed")
+
+	exists, _ = storage.Exists("file")
+	assert.True(t, exists, "File should be visible after close")
+}
+
+func TestWriterAbort(t *testing.T) {
+	directory := t.TempDir()
+	storage := archive.NewLocalStorage(directory)
+
+	manifest := writeArchive(t, storage, partition.Gzip)
+
+	writer, err := archive.NewWriter(storage, manifest.File, partition.Gzip)
+	assert.Nil(t, err, "NewWriter should succeed")
+
+	_, err = io.WriteString(writer, "truncated")
+	assert.Nil(t, err, "Write should succeed")
+	assert.Nil(t, writer.Abort(), "Abort should succeed")
+
+	// The archive of the previous run is left untouched, and the temporary file is removed
+	assert.Nil(t, archive.Verify(storage, manifest), "Previous archive should still be valid")
+
+	_, err = os.Stat(filepath.Join(directory, manifest.File+".tmp"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewStorage(t *testing.T) {
+	_, err := archive.NewStorage("/var/lib/ppm")
+	assert.Nil(t, err, "Local path should be supported")
+
+	_, err = archive.NewStorage("s3://bucket/path")
+	assert.ErrorIs(t, err, archive.ErrUnsupportedStorage)
+}
+
+func TestListManifests(t *testing.T) {
+	storage := archive.NewLocalStorage(filepath.Join(t.TempDir(), "missing"))
+
+	manifests, err := archive.ListManifests(storage)
+	assert.Nil(t, err, "ListManifests should succeed on a missing directory")
+	assert.Empty(t, manifests)
+
+	for _, compression := range []partition.ArchiveCompression{partition.Gzip, partition.Zstd} {
+		manifest := writeArchive(t, storage, compression)
+		manifest.Table += "_" + string(compression)
+		assert.Nil(t, archive.WriteManifest(storage, manifest), "WriteManifest should succeed")
+	}
+
+	manifests, err = archive.ListManifests(storage)
+	assert.Nil(t, err, "ListManifests should succeed")
+	assert.Len(t, manifests, 2)
+}
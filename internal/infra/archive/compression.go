@@ -0,0 +1,71 @@
+package archive
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var extensions = map[partition.ArchiveCompression]string{
+	partition.NoCompression: "",
+	partition.Gzip:          ".gz",
+	partition.Zstd:          ".zst",
+}
+
+// FileName returns the archive file name of a table, prefixed by its schema so tables of different schemas don't collide
+func FileName(schema, table string, format partition.ArchiveFormat, compression partition.ArchiveCompression) string {
+	return fmt.Sprintf("%s.%s.%s%s", schema, table, format, extensions[compression])
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// NewCompressor wraps the writer with the compression algorithm.
+// Closing the compressor flushes pending data but does not close the underlying writer.
+func NewCompressor(w io.Writer, compression partition.ArchiveCompression) (io.WriteCloser, error) {
+	switch compression {
+	case partition.NoCompression, "":
+		return nopWriteCloser{w}, nil
+	case partition.Gzip:
+		return gzip.NewWriter(w), nil
+	case partition.Zstd:
+		encoder, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize zstd encoder: %w", err)
+		}
+
+		return encoder, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCompression, compression)
+	}
+}
+
+// NewDecompressor wraps the reader with the decompression algorithm
+func NewDecompressor(r io.Reader, compression partition.ArchiveCompression) (io.ReadCloser, error) {
+	switch compression {
+	case partition.NoCompression, "":
+		return io.NopCloser(r), nil
+	case partition.Gzip:
+		reader, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize gzip decoder: %w", err)
+		}
+
+		return reader, nil
+	case partition.Zstd:
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize zstd decoder: %w", err)
+		}
+
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCompression, compression)
+	}
+}
@@ -0,0 +1,117 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	directoryPermission = 0o750
+	filePermission      = 0o640
+)
+
+// LocalStorage stores archive files in a directory of the local filesystem
+type LocalStorage struct {
+	directory string
+}
+
+func NewLocalStorage(directory string) *LocalStorage {
+	return &LocalStorage{directory: directory}
+}
+
+type localFile struct {
+	*os.File
+	path string
+}
+
+// Close renames the temporary file to its final name, so partially written files are never visible
+func (f localFile) Close() error {
+	if err := f.File.Sync(); err != nil {
+		f.Abort() //nolint:errcheck,gosec
+
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	if err := f.File.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	if err := os.Rename(f.File.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	return nil
+}
+
+// Abort closes and removes the temporary file
+func (f localFile) Abort() error {
+	f.File.Close() //nolint:errcheck,gosec
+
+	if err := os.Remove(f.File.Name()); err != nil {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+
+	return nil
+}
+
+func (s LocalStorage) Create(name string) (File, error) {
+	if err := os.MkdirAll(s.directory, directoryPermission); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	path := filepath.Join(s.directory, name)
+
+	file, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePermission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+
+	return localFile{File: file, path: path}, nil
+}
+
+func (s LocalStorage) Open(name string) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(s.directory, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return file, nil
+}
+
+func (s LocalStorage) Exists(name string) (bool, error) {
+	_, err := os.Stat(filepath.Join(s.directory, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s LocalStorage) List() (names []string, err error) {
+	entries, err := os.ReadDir(s.directory)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+
+	return names, nil
+}
@@ -0,0 +1,131 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var ErrChecksumMismatch = errors.New("archive checksum mismatch")
+
+// Manifest describes an archive file, it is stored next to it
+type Manifest struct {
+	Schema      string                       `json:"schema"`
+	Table       string                       `json:"table"`
+	ParentTable string                       `json:"parentTable"`
+	LowerBound  time.Time                    `json:"lowerBound"`
+	UpperBound  time.Time                    `json:"upperBound"`
+	File        string                       `json:"file"`
+	Format      partition.ArchiveFormat      `json:"format"`
+	Compression partition.ArchiveCompression `json:"compression"`
+	Rows        int64                        `json:"rows"`
+	Checksum    string                       `json:"checksum"` // SHA-256 of the archive file
+	Columns     string                       `json:"columns"`  // CREATE TABLE statement with the columns of the table, without defaults, constraints or indexes
+	CreatedAt   time.Time                    `json:"createdAt"`
+}
+
+const manifestSuffix = ".manifest.json"
+
+// ManifestName returns the manifest file name of a table, prefixed by its schema like the archive file
+func ManifestName(schema, table string) string {
+	return schema + "." + table + manifestSuffix
+}
+
+func WriteManifest(storage Storage, manifest Manifest) error {
+	w, err := storage.Create(ManifestName(manifest.Schema, manifest.Table))
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err = encoder.Encode(manifest); err != nil {
+		w.Abort() //nolint:errcheck,gosec
+
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	return w.Close()
+}
+
+func ReadManifest(storage Storage, schema, table string) (manifest Manifest, err error) {
+	return readManifest(storage, ManifestName(schema, table))
+}
+
+func readManifest(storage Storage, name string) (manifest Manifest, err error) {
+	r, err := storage.Open(name)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer r.Close() //nolint:errcheck
+
+	if err = json.NewDecoder(r).Decode(&manifest); err != nil {
+		return Manifest{}, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// ListManifests returns the manifests of all archives of the storage
+func ListManifests(storage Storage) (manifests []Manifest, err error) {
+	names, err := storage.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if !strings.HasSuffix(name, manifestSuffix) {
+			continue
+		}
+
+		manifest, err := readManifest(storage, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest %s: %w", name, err)
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	return manifests, nil
+}
+
+// Verify reads the archive file back, and checks it matches the manifest checksum and can be decompressed
+func Verify(storage Storage, manifest Manifest) error {
+	file, err := storage.Open(manifest.File)
+	if err != nil {
+		return err
+	}
+	defer file.Close() //nolint:errcheck
+
+	hash := sha256.New()
+	reader := io.TeeReader(file, hash)
+
+	decompressor, err := NewDecompressor(reader, manifest.Compression)
+	if err != nil {
+		return err
+	}
+	defer decompressor.Close() //nolint:errcheck
+
+	if _, err = io.Copy(io.Discard, decompressor); err != nil {
+		return fmt.Errorf("failed to decompress archive: %w", err)
+	}
+
+	// Hash trailing bytes not consumed by the decompressor
+	if _, err = io.Copy(io.Discard, reader); err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	if checksum := hex.EncodeToString(hash.Sum(nil)); checksum != manifest.Checksum {
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, manifest.Checksum, checksum)
+	}
+
+	return nil
+}
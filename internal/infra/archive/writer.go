@@ -0,0 +1,63 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+// Writer compresses data into an archive file and computes the checksum of the file
+type Writer struct {
+	file       File
+	compressor io.WriteCloser
+	hash       hash.Hash
+}
+
+func NewWriter(storage Storage, name string, compression partition.ArchiveCompression) (*Writer, error) {
+	file, err := storage.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.New()
+
+	compressor, err := NewCompressor(io.MultiWriter(file, checksum), compression)
+	if err != nil {
+		file.Abort() //nolint:errcheck,gosec
+
+		return nil, err
+	}
+
+	return &Writer{file: file, compressor: compressor, hash: checksum}, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.compressor.Write(p) //nolint:wrapcheck
+}
+
+// Close flushes the compressor and closes the archive file
+func (w *Writer) Close() error {
+	if err := w.compressor.Close(); err != nil {
+		w.file.Abort() //nolint:errcheck,gosec
+
+		return fmt.Errorf("failed to flush compressor: %w", err)
+	}
+
+	return w.file.Close() //nolint:wrapcheck
+}
+
+// Abort discards the archive file, so an incomplete archive never replaces an existing one
+func (w *Writer) Abort() error {
+	w.compressor.Close() //nolint:errcheck,gosec
+
+	return w.file.Abort() //nolint:wrapcheck
+}
+
+// Checksum returns the SHA-256 of the written archive file, it is only complete once the writer is closed
+func (w *Writer) Checksum() string {
+	return hex.EncodeToString(w.hash.Sum(nil))
+}
@@ -4,6 +4,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
@@ -21,7 +22,14 @@ type Config struct {
 func (c *Config) Check() error {
 	validate := validator.New()
 
-	err := validate.Struct(c)
+	err := validate.RegisterValidation("size", validateSize)
+	if err != nil {
+		return fmt.Errorf("could not register size validation: %w", err)
+	}
+
+	validate.RegisterStructValidation(validatePartition, partition.Configuration{})
+
+	err = validate.Struct(c)
 	if err != nil {
 		formatConfigurationError(err)
 
@@ -31,6 +39,38 @@ func (c *Config) Check() error {
 	return nil
 }
 
+// validateSize checks a size is parseable by partition.ParseSize
+func validateSize(fl validator.FieldLevel) bool {
+	_, err := partition.ParseSize(fl.Field().String())
+
+	return err == nil
+}
+
+// validatePartition checks the settings of a partition configuration depending on each other:
+// the table name and the generated names fit the PostgreSQL identifier limit, the precise retention matches the retention,
+// and the keepIf expression only uses known variables and queries with compatible types
+func validatePartition(sl validator.StructLevel) {
+	config, ok := sl.Current().Interface().(partition.Configuration)
+	if !ok {
+		return
+	}
+
+	err := config.CheckIdentifiers()
+	if err != nil {
+		sl.ReportError(config.Table, "Table", "Table", "identifier", err.Error())
+	}
+
+	err = config.CheckPreciseRetention()
+	if err != nil {
+		sl.ReportError(config.PreciseRetention, "PreciseRetention", "PreciseRetention", "preciseRetention", err.Error())
+	}
+
+	_, err = config.ParseKeepIf()
+	if err != nil {
+		sl.ReportError(config.KeepIf, "KeepIf", "KeepIf", "expression", err.Error())
+	}
+}
+
 func formatConfigurationError(err error) {
 	var invalidValidation *validator.InvalidValidationError
 
@@ -45,6 +85,16 @@ func formatConfigurationError(err error) {
 			switch e.Tag() {
 			case "required":
 				fmt.Printf("ERROR: The '%s' field is required and cannot be empty.\n", e.StructNamespace())
+			case "expression":
+				fmt.Printf("ERROR: The '%s' field must be a valid expression, but got '%s': %s.\n", e.StructNamespace(), e.Value(), e.Param())
+			case "identifier":
+				fmt.Printf("ERROR: The '%s' field generates invalid names: %s.\n", e.StructNamespace(), e.Param())
+			case "preciseRetention":
+				fmt.Printf("ERROR: The '%s' field does not match the retention: %s.\n", e.StructNamespace(), e.Param())
+			case "size":
+				fmt.Printf("ERROR: The '%s' field must be a size with an optional unit (B, kB, MB, GB, TB), but got '%s'.\n", e.StructNamespace(), e.Value())
+			case "excluded_unless":
+				fmt.Printf("ERROR: The '%s' field is only supported when %s.\n", e.StructNamespace(), strings.Replace(e.Param(), " ", " is ", 1))
 			case "oneof":
 				fmt.Printf("ERROR: The '%s' field must be one of [%s], but got '%s'.\n", e.StructNamespace(), e.Param(), e.Value())
 			default:
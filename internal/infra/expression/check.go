@@ -0,0 +1,123 @@
+package expression
+
+import (
+	"fmt"
+)
+
+// Type is the type of a value, known before evaluating the expression
+type Type int
+
+const (
+	Any    Type = iota // known once evaluated, such as the result of a query
+	Bool               // a boolean
+	Number             // a number
+	String             // a string, dates are YYYY-MM-DD strings
+)
+
+func (t Type) String() string {
+	switch t {
+	case Bool:
+		return "a boolean"
+	case Number:
+		return "a number"
+	case String:
+		return "a string"
+	default:
+		return "any value"
+	}
+}
+
+// Check returns an error when the expression uses a variable missing from types, combines values of incompatible types,
+// or does not return a boolean. Values of type Any are only checked during the evaluation.
+func (e Expression) Check(types map[string]Type) error {
+	result, err := e.root.check(types)
+	if err != nil {
+		return err
+	}
+
+	if result != Bool && result != Any {
+		return fmt.Errorf("%w: expression returns %s, expected a boolean", ErrType, result)
+	}
+
+	return nil
+}
+
+func (n literalNode) check(map[string]Type) (Type, error) {
+	switch n.value.(type) {
+	case bool:
+		return Bool, nil
+	case float64:
+		return Number, nil
+	default:
+		return String, nil
+	}
+}
+
+func (n variableNode) check(types map[string]Type) (Type, error) {
+	t, ok := types[n.name]
+	if !ok {
+		return Any, fmt.Errorf("%w: %s", ErrUnknownVariable, n.name)
+	}
+
+	return t, nil
+}
+
+func (n unaryNode) check(types map[string]Type) (Type, error) {
+	operand, err := n.operand.check(types)
+	if err != nil {
+		return Any, err
+	}
+
+	expected := Number
+	if n.operator == "!" {
+		expected = Bool
+	}
+
+	if operand != expected && operand != Any {
+		return Any, fmt.Errorf("%w: %s expects %s, got %s", ErrType, n.operator, expected, operand)
+	}
+
+	return expected, nil
+}
+
+func (n binaryNode) check(types map[string]Type) (Type, error) {
+	left, err := n.left.check(types)
+	if err != nil {
+		return Any, err
+	}
+
+	right, err := n.right.check(types)
+	if err != nil {
+		return Any, err
+	}
+
+	switch n.operator {
+	case "&&", "||":
+		if !accepts(left, Bool) || !accepts(right, Bool) {
+			return Any, fmt.Errorf("%w: %s expects booleans, got %s and %s", ErrType, n.operator, left, right)
+		}
+
+		return Bool, nil
+	case "==", "!=", "<", "<=", ">", ">=":
+		if left != Any && right != Any && left != right {
+			return Any, fmt.Errorf("%w: can't compare %s with %s", ErrType, left, right)
+		}
+
+		if n.operator != "==" && n.operator != "!=" && (left == Bool || right == Bool) {
+			return Any, fmt.Errorf("%w: %s expects numbers or strings, got %s", ErrType, n.operator, Bool)
+		}
+
+		return Bool, nil
+	default:
+		if !accepts(left, Number) || !accepts(right, Number) {
+			return Any, fmt.Errorf("%w: %s expects numbers, got %s and %s", ErrType, n.operator, left, right)
+		}
+
+		return Number, nil
+	}
+}
+
+// accepts returns true when a value of type t may be of the expected type
+func accepts(t, expected Type) bool {
+	return t == expected || t == Any
+}
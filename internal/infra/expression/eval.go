@@ -0,0 +1,198 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+type node interface {
+	eval(resolve Resolver) (any, error)
+	check(types map[string]Type) (Type, error)
+}
+
+type literalNode struct {
+	value any
+}
+
+func (n literalNode) eval(Resolver) (any, error) {
+	return n.value, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n variableNode) eval(resolve Resolver) (any, error) {
+	value, err := resolve(n.name)
+	if err != nil {
+		if errors.Is(err, ErrUnknownVariable) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownVariable, n.name)
+		}
+
+		return nil, fmt.Errorf("could not resolve %s: %w", n.name, err)
+	}
+
+	return normalize(value)
+}
+
+type unaryNode struct {
+	operator string
+	operand  node
+}
+
+func (n unaryNode) eval(resolve Resolver) (any, error) {
+	value, err := n.operand.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.operator {
+	case "!":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: ! expects a boolean, got %s", ErrType, typeName(value))
+		}
+
+		return !b, nil
+	default:
+		f, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: - expects a number, got %s", ErrType, typeName(value))
+		}
+
+		return -f, nil
+	}
+}
+
+type binaryNode struct {
+	operator    string
+	left, right node
+}
+
+func (n binaryNode) eval(resolve Resolver) (any, error) {
+	left, err := n.left.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	// Logical operators short-circuit, so the right operand variables are only resolved when needed
+	if n.operator == "&&" || n.operator == "||" {
+		return n.evalLogical(left, resolve)
+	}
+
+	right, err := n.right.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.operator {
+	case "==":
+		return left == right, nil
+	case "!=":
+		return left != right, nil
+	case "<", "<=", ">", ">=":
+		return n.evalComparison(left, right)
+	default:
+		return n.evalArithmetic(left, right)
+	}
+}
+
+func (n binaryNode) evalLogical(left any, resolve Resolver) (any, error) {
+	l, ok := left.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects booleans, got %s", ErrType, n.operator, typeName(left))
+	}
+
+	if (n.operator == "&&" && !l) || (n.operator == "||" && l) {
+		return l, nil
+	}
+
+	right, err := n.right.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := right.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s expects booleans, got %s", ErrType, n.operator, typeName(right))
+	}
+
+	return r, nil
+}
+
+// evalComparison compares two numbers or two strings, dates are compared as YYYY-MM-DD strings
+func (n binaryNode) evalComparison(left, right any) (any, error) {
+	var cmp int
+
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: can't compare a number with %s", ErrType, typeName(right))
+		}
+
+		cmp = compare(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: can't compare a string with %s", ErrType, typeName(right))
+		}
+
+		cmp = compare(l, r)
+	default:
+		return nil, fmt.Errorf("%w: %s expects numbers or strings, got %s", ErrType, n.operator, typeName(left))
+	}
+
+	switch n.operator {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func (n binaryNode) evalArithmetic(left, right any) (any, error) {
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+
+	if !lok || !rok {
+		return nil, fmt.Errorf("%w: %s expects numbers, got %s and %s", ErrType, n.operator, typeName(left), typeName(right))
+	}
+
+	switch n.operator {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, ErrDivisionByZero
+		}
+
+		return l / r, nil
+	default:
+		if r == 0 {
+			return nil, ErrDivisionByZero
+		}
+
+		return math.Mod(l, r), nil
+	}
+}
+
+func compare[T float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
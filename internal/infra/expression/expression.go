@@ -0,0 +1,125 @@
+// Package expression provides a small expression language to evaluate retention rules.
+//
+// Expressions support number, string and boolean literals, variables, parentheses,
+// arithmetic (+ - * / %), comparisons (== != < <= > >=) and logical operators (&& || !).
+// Variables are resolved lazily, so variables that are not needed to compute the result are never resolved.
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrSyntax          = errors.New("syntax error")
+	ErrUnknownVariable = errors.New("unknown variable")
+	ErrType            = errors.New("type error")
+	ErrDivisionByZero  = errors.New("division by zero")
+)
+
+// Resolver returns the value of a variable.
+// It returns ErrUnknownVariable for variables it does not know.
+type Resolver func(name string) (any, error)
+
+// Expression is a parsed expression
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse parses the source of an expression
+func Parse(source string) (Expression, error) {
+	p := parser{lexer: lexer{source: source}}
+
+	err := p.next()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	if p.token.kind != endToken {
+		return Expression{}, fmt.Errorf("%w: unexpected %q at position %d", ErrSyntax, p.token.text, p.token.position)
+	}
+
+	return Expression{source: source, root: root}, nil
+}
+
+func (e Expression) String() string {
+	return e.source
+}
+
+// Evaluate returns the value of the expression: a float64, a string or a bool
+func (e Expression) Evaluate(resolve Resolver) (any, error) {
+	return e.root.eval(resolve)
+}
+
+// EvaluateBool returns the value of an expression expected to be a condition
+func (e Expression) EvaluateBool(resolve Resolver) (bool, error) {
+	value, err := e.Evaluate(resolve)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: expression returns %s, expected a boolean", ErrType, typeName(value))
+	}
+
+	return result, nil
+}
+
+// normalize converts resolved values to the types handled by the evaluator.
+// SQL NULL values are kept as nil, they are only equal to other NULL values.
+func normalize(value any) (any, error) {
+	switch v := value.(type) {
+	case nil, bool, string, float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case time.Time:
+		return v.Format("2006-01-02"), nil
+	case pgtype.Numeric:
+		if !v.Valid {
+			return nil, nil
+		}
+
+		f, err := v.Float64Value()
+		if err != nil {
+			return nil, fmt.Errorf("%w: unsupported numeric %v: %w", ErrType, value, err)
+		}
+
+		return f.Float64, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported value %v (%T)", ErrType, value, value)
+	}
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case bool:
+		return "a boolean"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,157 @@
+package expression_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/expression"
+	"github.com/stretchr/testify/assert"
+)
+
+var errQueryFailed = errors.New("query failed")
+
+func resolver(variables map[string]any) expression.Resolver {
+	return func(name string) (any, error) {
+		value, ok := variables[name]
+		if !ok {
+			return nil, expression.ErrUnknownVariable
+		}
+
+		if err, ok := value.(error); ok {
+			return nil, err
+		}
+
+		return value, nil
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	variables := map[string]any{
+		"quarter":              4,
+		"age":                  int64(800),
+		"lower_bound":          time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC),
+		"query.not_exported":   false,
+		"query.failing":        errQueryFailed,
+		"size":                 float32(1.5),
+		"retention_years_days": 7 * 365,
+		"query.total":          pgtype.Numeric{Int: big.NewInt(1250), Exp: -2, Valid: true},
+		"query.missing":        nil,
+	}
+
+	testCases := []struct {
+		source   string
+		expected any
+	}{
+		{"1 + 2 * 3", 7.0},
+		{"(1 + 2) * 3", 9.0},
+		{"-age + 1000", 200.0},
+		{"10 % 4", 2.0},
+		{"quarter == 4 && age < 7 * 365", true},
+		{"quarter == 4 && age < retention_years_days", true},
+		{"quarter != 4 || age < 365", false},
+		{"!query.not_exported", true},
+		{"lower_bound >= '2022-01-01' && lower_bound < \"2023-01-01\"", true},
+		{"lower_bound == '2022-10-01'", true},
+		{"size > 1", true},
+		{"query.total > 12", true},
+		{"query.missing == query.missing", true},
+		// The right operand is not evaluated when the left operand decides the result
+		{"quarter == 3 && query.failing", false},
+		{"quarter == 4 || query.failing", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			parsed, err := expression.Parse(tc.source)
+			assert.Nil(t, err, "Parse should succeed")
+
+			value, err := parsed.Evaluate(resolver(variables))
+			assert.Nil(t, err, "Evaluate should succeed")
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, source := range []string{"", "1 +", "(1 + 2", "1 2", "'unterminated", "age # 2", "&& true"} {
+		t.Run(source, func(t *testing.T) {
+			_, err := expression.Parse(source)
+			assert.ErrorIs(t, err, expression.ErrSyntax)
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	variables := map[string]any{
+		"age":           1,
+		"name":          "my_table",
+		"query.failing": errQueryFailed,
+	}
+
+	testCases := []struct {
+		source   string
+		expected error
+	}{
+		{"unknown > 1", expression.ErrUnknownVariable},
+		{"age > name", expression.ErrType},
+		{"age && true", expression.ErrType},
+		{"!age", expression.ErrType},
+		{"age / 0 > 1", expression.ErrDivisionByZero},
+		{"query.failing", errQueryFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			parsed, err := expression.Parse(tc.source)
+			assert.Nil(t, err, "Parse should succeed")
+
+			_, err = parsed.Evaluate(resolver(variables))
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+
+	parsed, _ := expression.Parse("age + 1")
+	_, err := parsed.EvaluateBool(resolver(variables))
+	assert.ErrorIs(t, err, expression.ErrType, "EvaluateBool should reject non boolean results")
+}
+
+func TestCheck(t *testing.T) {
+	types := map[string]expression.Type{
+		"age":          expression.Number,
+		"lower_bound":  expression.String,
+		"query.exists": expression.Any,
+	}
+
+	testCases := []struct {
+		source   string
+		expected error
+	}{
+		{"age > 365 && lower_bound >= '2022-01-01'", nil},
+		{"query.exists", nil},
+		{"!query.exists || query.exists > 1", nil},
+		{"unknown > 1", expression.ErrUnknownVariable},
+		{"query.unknown", expression.ErrUnknownVariable},
+		{"age > lower_bound", expression.ErrType},
+		{"age && true", expression.ErrType},
+		{"-lower_bound < 1", expression.ErrType},
+		{"true < false", expression.ErrType},
+		{"age + 1", expression.ErrType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			parsed, err := expression.Parse(tc.source)
+			assert.Nil(t, err, "Parse should succeed")
+
+			err = parsed.Check(types)
+			if tc.expected == nil {
+				assert.Nil(t, err, "Check should succeed")
+			} else {
+				assert.ErrorIs(t, err, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	numberToken
+	stringToken
+	identifierToken
+	operatorToken
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")"}
+
+type lexer struct {
+	source   string
+	position int
+}
+
+func (l *lexer) next() (token, error) {
+	for l.position < len(l.source) && unicode.IsSpace(rune(l.source[l.position])) {
+		l.position++
+	}
+
+	start := l.position
+	if start >= len(l.source) {
+		return token{kind: endToken, position: start}, nil
+	}
+
+	c := l.source[start]
+
+	switch {
+	case c >= '0' && c <= '9':
+		for l.position < len(l.source) && (isDigit(l.source[l.position]) || l.source[l.position] == '.') {
+			l.position++
+		}
+
+		return token{kind: numberToken, text: l.source[start:l.position], position: start}, nil
+	case c == '"' || c == '\'':
+		end := strings.IndexByte(l.source[start+1:], c)
+		if end < 0 {
+			return token{}, fmt.Errorf("%w: unterminated string at position %d", ErrSyntax, start)
+		}
+
+		l.position = start + end + 2
+
+		return token{kind: stringToken, text: l.source[start+1 : start+end+1], position: start}, nil
+	case isLetter(c):
+		// Dots are part of identifiers, so query results are named query.<name>
+		for l.position < len(l.source) && (isLetter(l.source[l.position]) || isDigit(l.source[l.position]) || l.source[l.position] == '.') {
+			l.position++
+		}
+
+		return token{kind: identifierToken, text: l.source[start:l.position], position: start}, nil
+	}
+
+	for _, operator := range operators {
+		if strings.HasPrefix(l.source[start:], operator) {
+			l.position += len(operator)
+
+			return token{kind: operatorToken, text: operator, position: start}, nil
+		}
+	}
+
+	return token{}, fmt.Errorf("%w: unexpected character %q at position %d", ErrSyntax, c, start)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// parser is a recursive descent parser, one method per precedence level
+type parser struct {
+	lexer lexer
+	token token
+}
+
+func (p *parser) next() (err error) {
+	p.token, err = p.lexer.next()
+
+	return err
+}
+
+func (p *parser) isOperator(operators ...string) bool {
+	if p.token.kind != operatorToken {
+		return false
+	}
+
+	for _, operator := range operators {
+		if p.token.text == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseBinary parses operands separated by one of the operators, left to right
+func (p *parser) parseBinary(operand func() (node, error), operators ...string) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(operators...) {
+		operator := p.token.text
+
+		err = p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryNode{operator: operator, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseBinary(p.parseComparison, "&&")
+}
+
+func (p *parser) parseComparison() (node, error) {
+	return p.parseBinary(p.parseAdditive, "==", "!=", "<", "<=", ">", ">=")
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseBinary(p.parseMultiplicative, "+", "-")
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if !p.isOperator("!", "-") {
+		return p.parsePrimary()
+	}
+
+	operator := p.token.text
+
+	err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return unaryNode{operator: operator, operand: operand}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	current := p.token
+
+	switch current.kind {
+	case numberToken:
+		value, err := strconv.ParseFloat(current.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid number %q at position %d", ErrSyntax, current.text, current.position)
+		}
+
+		return literalNode{value: value}, p.next()
+	case stringToken:
+		return literalNode{value: current.text}, p.next()
+	case identifierToken:
+		switch current.text {
+		case "true":
+			return literalNode{value: true}, p.next()
+		case "false":
+			return literalNode{value: false}, p.next()
+		default:
+			return variableNode{name: current.text}, p.next()
+		}
+	case operatorToken:
+		if current.text != "(" {
+			break
+		}
+
+		err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.isOperator(")") {
+			return nil, fmt.Errorf("%w: missing closing parenthesis at position %d", ErrSyntax, p.token.position)
+		}
+
+		return inner, p.next()
+	case endToken:
+		return nil, fmt.Errorf("%w: unexpected end of expression", ErrSyntax)
+	}
+
+	return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrSyntax, current.text, current.position)
+}
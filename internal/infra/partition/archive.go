@@ -0,0 +1,40 @@
+package partition
+
+type (
+	ArchiveFormat      string
+	ArchiveCompression string
+)
+
+const (
+	CSV    ArchiveFormat = "csv"
+	Binary ArchiveFormat = "binary"
+
+	NoCompression ArchiveCompression = "none"
+	Gzip          ArchiveCompression = "gzip"
+	Zstd          ArchiveCompression = "zstd"
+)
+
+// ArchiveConfiguration describes where and how partitions are exported before being dropped
+type ArchiveConfiguration struct {
+	Destination string             `mapstructure:"destination" validate:"required"`
+	Format      ArchiveFormat      `mapstructure:"format" validate:"omitempty,oneof=csv binary"`
+	Compression ArchiveCompression `mapstructure:"compression" validate:"omitempty,oneof=none gzip zstd"`
+}
+
+// GetFormat returns the archive format, CSV by default
+func (a ArchiveConfiguration) GetFormat() ArchiveFormat {
+	if a.Format == "" {
+		return CSV
+	}
+
+	return a.Format
+}
+
+// GetCompression returns the archive compression, none by default
+func (a ArchiveConfiguration) GetCompression() ArchiveCompression {
+	if a.Compression == "" {
+		return NoCompression
+	}
+
+	return a.Compression
+}
@@ -0,0 +1,12 @@
+package partition
+
+// SizeBudgetConfiguration removes the oldest partitions while the table is larger than a size budget
+type SizeBudgetConfiguration struct {
+	MaxSize       string `mapstructure:"maxSize" validate:"required,size"`        // e.g. "500GB"
+	MinPartitions int    `mapstructure:"minPartitions" validate:"omitempty,gt=0"` // partitions always kept, current and pre-provisioned included
+}
+
+// GetMaxSize returns the size budget in bytes
+func (b SizeBudgetConfiguration) GetMaxSize() (int64, error) {
+	return ParseSize(b.MaxSize)
+}
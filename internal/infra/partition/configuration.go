@@ -10,21 +10,45 @@ type (
 )
 
 const (
-	Drop   CleanupPolicy = "drop"
-	Detach CleanupPolicy = "detach"
+	Drop     CleanupPolicy = "drop"
+	Detach   CleanupPolicy = "detach"
+	Truncate CleanupPolicy = "truncate"
 )
 
 type Configuration struct {
-	Schema         string        `mapstructure:"schema" validate:"required"`
-	Table          string        `mapstructure:"table" validate:"required"`
-	PartitionKey   string        `mapstructure:"partitionKey" validate:"required"`
-	Interval       Interval      `mapstructure:"interval" validate:"required,oneof=daily weekly monthly quarterly yearly"`
-	Retention      int           `mapstructure:"retention" validate:"required,gt=0"`
-	PreProvisioned int           `mapstructure:"preProvisioned" validate:"required,gt=0"`
-	CleanupPolicy  CleanupPolicy `mapstructure:"cleanupPolicy" validate:"required,oneof=drop detach"`
+	Schema           string                         `mapstructure:"schema" validate:"required"`
+	Table            string                         `mapstructure:"table" validate:"required"`
+	PartitionKey     string                         `mapstructure:"partitionKey" validate:"required"`
+	Interval         Interval                       `mapstructure:"interval" validate:"required,oneof=daily weekly monthly quarterly yearly"`
+	Retention        int                            `mapstructure:"retention" validate:"required,gt=0"`
+	PreProvisioned   int                            `mapstructure:"preProvisioned" validate:"required,gt=0"`
+	CleanupPolicy    CleanupPolicy                  `mapstructure:"cleanupPolicy" validate:"required,oneof=drop detach truncate"`
+	ReadOnlyAfter    int                            `mapstructure:"readOnlyAfter" validate:"omitempty,gt=0"`
+	CheckIndexes     bool                           `mapstructure:"checkIndexes"`
+	Indexes          []IndexRule                    `mapstructure:"indexes" validate:"omitempty,dive"`
+	Publications     []string                       `mapstructure:"publications" validate:"omitempty,dive,required"`
+	Archive          *ArchiveConfiguration          `mapstructure:"archive" validate:"omitempty"`
+	DetachedSchema   string                         `mapstructure:"detachedSchema"`
+	RenameDetached   bool                           `mapstructure:"renameDetached"`
+	GracePeriod      int                            `mapstructure:"gracePeriod" validate:"omitempty,gt=0,excluded_unless=CleanupPolicy drop"`
+	Safeguards       *SafeguardConfiguration        `mapstructure:"safeguards" validate:"omitempty"`
+	Holds            []Hold                         `mapstructure:"holds" validate:"omitempty,dive"`
+	Recycle          bool                           `mapstructure:"recycle"`
+	PreciseRetention *PreciseRetentionConfiguration `mapstructure:"preciseRetention" validate:"omitempty"`
+	SizeBudget       *SizeBudgetConfiguration       `mapstructure:"sizeBudget" validate:"omitempty"`
+	Tiers            []RetentionTier                `mapstructure:"tiers" validate:"omitempty,dive"`
+	KeepIf           string                         `mapstructure:"keepIf"`
+	Queries          map[string]string              `mapstructure:"queries" validate:"omitempty,dive,keys,required,endkeys,required"`
+	RetentionGate    string                         `mapstructure:"retentionGate"`
+	Sparse           bool                           `mapstructure:"sparse"`
+	StrictNames      bool                           `mapstructure:"strictNames"`
 }
 
 func (p Configuration) GeneratePartition(forDate time.Time) (Partition, error) {
+	if len(p.Table) > MaxIdentifierLength {
+		return Partition{}, fmt.Errorf("%w: %s", ErrIdentifierTooLong, p.Table)
+	}
+
 	var suffix string
 
 	var lowerBound, upperBound time.Time
@@ -68,7 +92,7 @@ func (p Configuration) GeneratePartition(forDate time.Time) (Partition, error) {
 	partition := Partition{
 		Schema:      p.Schema,
 		ParentTable: p.Table,
-		Name:        fmt.Sprintf("%s_%s", p.Table, suffix),
+		Name:        p.partitionName(suffix),
 		LowerBound:  lowerBound,
 		UpperBound:  upperBound,
 	}
@@ -76,6 +100,11 @@ func (p Configuration) GeneratePartition(forDate time.Time) (Partition, error) {
 	return partition, nil
 }
 
+// SegmentName returns the name of a partition whose bounds don't follow the interval, such as the segments created by provisioning
+func (p Configuration) SegmentName(lowerBound, upperBound time.Time) string {
+	return p.partitionName(fmt.Sprintf("%s_%s", lowerBound.Format("20060102"), upperBound.Format("20060102")))
+}
+
 func (p Configuration) GetRetentionPartitions(forDate time.Time) ([]Partition, error) {
 	partitions := make([]Partition, p.Retention)
 
@@ -116,6 +145,51 @@ func (p Configuration) GetPreProvisionedPartitions(forDate time.Time) ([]Partiti
 	return partitions, nil
 }
 
+// AgeBoundary returns the date before which partitions are at least age intervals old.
+// The current partition has an age of 0, the previous one an age of 1, and so on.
+// A partition whose upper bound is before or equal to the boundary is at least age intervals old.
+func (p Configuration) AgeBoundary(forDate time.Time, age int) (time.Time, error) {
+	prevDate, err := p.getPrevDate(forDate, age-1)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not compute previous date: %w", err)
+	}
+
+	partition, err := p.GeneratePartition(prevDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not generate partition: %w", err)
+	}
+
+	return partition.LowerBound, nil
+}
+
+// IsInAgeRange returns true when the partition is at least minAge intervals old and younger than maxAge intervals.
+// A minAge of 0 includes future partitions, a maxAge of 0 disables the upper limit.
+func (p Configuration) IsInAgeRange(partition Partition, forDate time.Time, minAge, maxAge int) (bool, error) {
+	if minAge > 0 {
+		boundary, err := p.AgeBoundary(forDate, minAge)
+		if err != nil {
+			return false, err
+		}
+
+		if partition.UpperBound.After(boundary) {
+			return false, nil
+		}
+	}
+
+	if maxAge > 0 {
+		boundary, err := p.AgeBoundary(forDate, maxAge)
+		if err != nil {
+			return false, err
+		}
+
+		if !partition.UpperBound.After(boundary) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (p Configuration) getPrevDate(forDate time.Time, i int) (t time.Time, err error) {
 	switch p.Interval {
 	case Daily:
@@ -560,3 +560,95 @@ func TestGetPreProvisionedPartitionsContiguity(t *testing.T) {
 		})
 	}
 }
+
+// --- Age boundary tests ---
+
+func TestAgeBoundary(t *testing.T) {
+	testCases := []struct {
+		name     string
+		interval Interval
+		forDate  time.Time
+		age      int
+		expected time.Time
+	}{
+		{
+			name:     "Daily age 1 is the current partition lower bound",
+			interval: Daily,
+			forDate:  time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC),
+			age:      1,
+			expected: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Daily age 3",
+			interval: Daily,
+			forDate:  time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			age:      3,
+			expected: time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Monthly age 2",
+			interval: Monthly,
+			forDate:  time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			age:      2,
+			expected: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Quarterly age 1",
+			interval: Quarterly,
+			forDate:  time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC),
+			age:      1,
+			expected: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Yearly age 2",
+			interval: Yearly,
+			forDate:  time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+			age:      2,
+			expected: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := configForInterval(tc.interval, 1, 1)
+			result, err := config.AgeBoundary(tc.forDate, tc.age)
+			assert.NilError(t, err)
+			assert.Equal(t, result, tc.expected)
+		})
+	}
+}
+
+func TestIsInAgeRange(t *testing.T) {
+	config := configForInterval(Monthly, 12, 2)
+	forDate := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	future, _ := config.GeneratePartition(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+	current, _ := config.GeneratePartition(forDate)
+	previous, _ := config.GeneratePartition(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	old, _ := config.GeneratePartition(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name      string
+		partition Partition
+		minAge    int
+		maxAge    int
+		expected  bool
+	}{
+		{"Future partition in hot stage", future, 0, 1, true},
+		{"Current partition in hot stage", current, 0, 1, true},
+		{"Previous partition out of hot stage", previous, 0, 1, false},
+		{"Current partition out of history stage", current, 1, 0, false},
+		{"Previous partition in history stage", previous, 1, 0, true},
+		{"Old partition in history stage", old, 1, 0, true},
+		{"Old partition after bounded stage", old, 1, 3, false},
+		{"Previous partition in bounded stage", previous, 1, 3, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := config.IsInAgeRange(tc.partition, forDate, tc.minAge, tc.maxAge)
+			assert.NilError(t, err)
+			assert.Equal(t, result, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package partition
+
+import (
+	"time"
+)
+
+// Hold keeps the partitions overlapping a date range past their retention, for example for a legal requirement
+type Hold struct {
+	From    time.Time `mapstructure:"from" validate:"required"` // first held date
+	To      time.Time `mapstructure:"to" validate:"required"`   // last held date, included
+	Expires time.Time `mapstructure:"expires"`                  // date the hold ends, never when empty
+	Reason  string    `mapstructure:"reason"`
+}
+
+// Covers returns true when the hold keeps the partition at the given date
+func (h Hold) Covers(part Partition, at time.Time) bool {
+	if !h.Expires.IsZero() && !at.Before(h.Expires) {
+		return false
+	}
+
+	return part.LowerBound.Before(h.To.AddDate(0, 0, 1)) && part.UpperBound.After(h.From)
+}
+
+// GetHold returns true when a hold of the configuration or of the partition metadata keeps the partition at the given date, with the reason of the hold
+func (p Configuration) GetHold(part Partition, at time.Time) (held bool, reason string) {
+	for _, hold := range p.Holds {
+		if hold.Covers(part, at) {
+			return true, hold.Reason
+		}
+	}
+
+	if part.Metadata.IsHeld(at) {
+		return true, part.Metadata.HoldReason
+	}
+
+	return false, ""
+}
@@ -0,0 +1,62 @@
+package partition
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestHoldCovers(t *testing.T) {
+	at := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	hold := Hold{From: date(2024, 3, 1), To: date(2024, 3, 31), Reason: "Case 42"}
+
+	testCases := []struct {
+		name     string
+		hold     Hold
+		part     Partition
+		expected bool
+	}{
+		{"First day", hold, dailyPartition(2024, 3, 1), true},
+		{"Last day", hold, dailyPartition(2024, 3, 31), true},
+		{"Before", hold, dailyPartition(2024, 2, 29), false},
+		{"After", hold, dailyPartition(2024, 4, 1), false},
+		{"Overlapping month", hold, Partition{LowerBound: date(2024, 2, 1), UpperBound: date(2024, 3, 1).AddDate(0, 0, 1)}, true},
+		{"Not expired", Hold{From: date(2024, 3, 1), To: date(2024, 3, 31), Expires: date(2024, 6, 16)}, dailyPartition(2024, 3, 1), true},
+		{"Expired", Hold{From: date(2024, 3, 1), To: date(2024, 3, 31), Expires: date(2024, 6, 15)}, dailyPartition(2024, 3, 1), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.hold.Covers(tc.part, at), tc.expected)
+		})
+	}
+}
+
+func TestGetHold(t *testing.T) {
+	at := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := Configuration{Holds: []Hold{{From: date(2024, 3, 1), To: date(2024, 3, 31), Reason: "Case 42"}}}
+
+	held, reason := config.GetHold(dailyPartition(2024, 3, 10), at)
+	assert.Assert(t, held)
+	assert.Equal(t, reason, "Case 42")
+
+	part := dailyPartition(2024, 5, 10)
+	held, _ = config.GetHold(part, at)
+	assert.Assert(t, !held)
+
+	part.Metadata = Metadata{HoldUntil: at.AddDate(0, 0, 1), HoldReason: "Audit"}
+	held, reason = config.GetHold(part, at)
+	assert.Assert(t, held)
+	assert.Equal(t, reason, "Audit")
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func dailyPartition(year int, month time.Month, day int) Partition {
+	lowerBound := date(year, month, day)
+
+	return Partition{LowerBound: lowerBound, UpperBound: lowerBound.AddDate(0, 0, 1)}
+}
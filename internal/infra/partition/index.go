@@ -0,0 +1,30 @@
+package partition
+
+import "fmt"
+
+// IndexRule declares an index that only exists on partitions within an age stage.
+// The age of the current partition is 0, future partitions are part of the stage starting at age 0.
+// A MaxAge of 0 means the stage has no upper limit.
+type IndexRule struct {
+	Name       string `mapstructure:"name" validate:"required"`
+	Definition string `mapstructure:"definition" validate:"required"`
+	MinAge     int    `mapstructure:"minAge" validate:"gte=0"`
+	MaxAge     int    `mapstructure:"maxAge" validate:"omitempty,gtfield=MinAge"`
+}
+
+// IndexName returns the name of the index created by the rule on the partition
+func (r IndexRule) IndexName(partition Partition) string {
+	return PartitionIndexName(partition, r.Name)
+}
+
+// PartitionIndexName returns the name of an index created by PPM on the partition, <partition>_<name>.
+// Partition names may already fill the identifier limit, so index names are shortened like partition names,
+// with a hash of the partition name.
+func PartitionIndexName(partition Partition, name string) string {
+	return shortenIdentifier(partition.Name, name)
+}
+
+// LegacyPartitionIndexName returns the name PostgreSQL gave to an index created by PPM before index names were shortened
+func LegacyPartitionIndexName(partition Partition, name string) string {
+	return TruncatedIdentifier(fmt.Sprintf("%s_%s", partition.Name, name))
+}
@@ -0,0 +1,47 @@
+package partition
+
+import (
+	"fmt"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/expression"
+)
+
+// KeepIfVariables returns the variables of the keepIf expression with their types.
+// Query results are only typed once evaluated.
+func (p Configuration) KeepIfVariables() map[string]expression.Type {
+	variables := map[string]expression.Type{
+		"lower_bound": expression.String,
+		"upper_bound": expression.String,
+		"year":        expression.Number,
+		"quarter":     expression.Number,
+		"month":       expression.Number,
+		"age":         expression.Number,
+		"rows":        expression.Number,
+		"size":        expression.Number,
+	}
+
+	for name := range p.Queries {
+		variables["query."+name] = expression.Any
+	}
+
+	return variables
+}
+
+// ParseKeepIf parses the keepIf expression and checks its variables and types, it returns nil when keepIf is not set
+func (p Configuration) ParseKeepIf() (*expression.Expression, error) {
+	if p.KeepIf == "" {
+		return nil, nil
+	}
+
+	keepIf, err := expression.Parse(p.KeepIf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keepIf expression: %w", err)
+	}
+
+	err = keepIf.Check(p.KeepIfVariables())
+	if err != nil {
+		return nil, fmt.Errorf("invalid keepIf expression: %w", err)
+	}
+
+	return &keepIf, nil
+}
@@ -0,0 +1,36 @@
+package partition
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/expression"
+	"gotest.tools/assert"
+)
+
+func TestParseKeepIf(t *testing.T) {
+	testCases := []struct {
+		keepIf   string
+		expected error
+	}{
+		{"", nil},
+		{"quarter == 4 && age < 7 * 365", nil},
+		{"query.has_open_invoices", nil},
+		{"query.unknown", expression.ErrUnknownVariable},
+		{"lower_bound > 2022", expression.ErrType},
+		{"age +", expression.ErrSyntax},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.keepIf, func(t *testing.T) {
+			config := Configuration{KeepIf: tc.keepIf, Queries: map[string]string{"has_open_invoices": "SELECT true"}}
+
+			_, err := config.ParseKeepIf()
+			if tc.expected == nil {
+				assert.NilError(t, err)
+			} else {
+				assert.Assert(t, errors.Is(err, tc.expected))
+			}
+		})
+	}
+}
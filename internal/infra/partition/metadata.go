@@ -0,0 +1,117 @@
+package partition
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var ErrForeignComment = errors.New("table comment is not a JSON object and would be overwritten")
+
+// Metadata is the state recorded by PPM on a partition or on a parent table, stored as JSON in the table comment
+type Metadata struct {
+	HoldUntil    time.Time `json:"holdUntil,omitzero"`     // cleanup keeps the partition until this date
+	HoldReason   string    `json:"holdReason,omitempty"`   // why the partition is held
+	DetachedAt   time.Time `json:"detachedAt,omitzero"`    // date the partition was detached by cleanup
+	ParentTable  string    `json:"parentTable,omitempty"`  // qualified name of the parent table of a detached partition
+	OriginalName string    `json:"originalName,omitempty"` // qualified name of a detached partition before it was moved
+	LowerBound   time.Time `json:"lowerBound,omitzero"`    // bounds of a detached partition, used to attach it again
+	UpperBound   time.Time `json:"upperBound,omitzero"`
+
+	BudgetedUntil time.Time `json:"budgetedUntil,omitzero"` // partitions of a parent table before this date were removed by the size budget
+}
+
+// ParseMetadata decodes a table comment. Comments not written by PPM result in empty metadata.
+func ParseMetadata(comment string) Metadata {
+	var metadata Metadata
+
+	if err := json.Unmarshal([]byte(comment), &metadata); err != nil {
+		return Metadata{}
+	}
+
+	return metadata
+}
+
+// Encode returns the table comment storing the metadata
+func (m Metadata) Encode() (string, error) {
+	comment, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode partition metadata: %w", err)
+	}
+
+	return string(comment), nil
+}
+
+// MergeComment returns the table comment storing the metadata.
+// Fields of a JSON comment that are not metadata are kept, so users can store their own fields next to the metadata.
+// Comments that are not a JSON object are refused instead of being overwritten.
+func (m Metadata) MergeComment(comment string) (string, error) {
+	fields := make(map[string]json.RawMessage)
+
+	if strings.TrimSpace(comment) != "" {
+		if err := json.Unmarshal([]byte(comment), &fields); err != nil || fields == nil {
+			return "", fmt.Errorf("%w: %q", ErrForeignComment, comment)
+		}
+	}
+
+	// Metadata fields are all replaced, so zero values remove previous ones
+	for _, key := range metadataKeys() {
+		delete(fields, key)
+	}
+
+	if len(fields) == 0 {
+		if m == (Metadata{}) {
+			return "", nil
+		}
+
+		return m.Encode()
+	}
+
+	encoded, err := m.Encode()
+	if err != nil {
+		return "", err
+	}
+
+	var metadata map[string]json.RawMessage
+	if err = json.Unmarshal([]byte(encoded), &metadata); err != nil {
+		return "", fmt.Errorf("failed to decode partition metadata: %w", err)
+	}
+
+	maps.Copy(fields, metadata)
+
+	merged, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode table comment: %w", err)
+	}
+
+	return string(merged), nil
+}
+
+// metadataKeys returns the JSON keys of the metadata fields
+func metadataKeys() []string {
+	metadataType := reflect.TypeFor[Metadata]()
+	keys := make([]string, 0, metadataType.NumField())
+
+	for i := range metadataType.NumField() {
+		key, _, _ := strings.Cut(metadataType.Field(i).Tag.Get("json"), ",")
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// DetachedFromMarker returns the part of the comment of partitions detached from the parent table, used to find them in the catalog
+func DetachedFromMarker(parentTable string) string {
+	encoded, _ := json.Marshal(parentTable) //nolint:errchkjson // strings are always encodable
+
+	return `"parentTable":` + string(encoded)
+}
+
+// IsHeld returns true when the partition must be kept by cleanup at the given date
+func (m Metadata) IsHeld(at time.Time) bool {
+	return at.Before(m.HoldUntil)
+}
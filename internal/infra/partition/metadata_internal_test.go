@@ -0,0 +1,74 @@
+package partition
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestMetadata(t *testing.T) {
+	holdUntil := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	comment, err := Metadata{HoldUntil: holdUntil}.Encode()
+	assert.NilError(t, err)
+	assert.Equal(t, comment, `{"holdUntil":"2024-06-15T00:00:00Z"}`)
+
+	metadata := ParseMetadata(comment)
+	assert.Assert(t, metadata.HoldUntil.Equal(holdUntil))
+	assert.Assert(t, metadata.IsHeld(holdUntil.AddDate(0, 0, -1)))
+	assert.Assert(t, !metadata.IsHeld(holdUntil))
+
+	empty, err := Metadata{}.Encode()
+	assert.NilError(t, err)
+	assert.Equal(t, empty, `{}`)
+
+	assert.Equal(t, ParseMetadata("Comment written by a user"), Metadata{})
+	assert.Equal(t, ParseMetadata(""), Metadata{})
+	assert.Assert(t, !Metadata{}.IsHeld(holdUntil))
+}
+
+func TestMergeComment(t *testing.T) {
+	holdUntil := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	detachedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		metadata Metadata
+		comment  string
+		expected string
+		err      error
+	}{
+		{"No comment", Metadata{HoldUntil: holdUntil}, "", `{"holdUntil":"2024-06-15T00:00:00Z"}`, nil},
+		{"Metadata comment is replaced", Metadata{HoldUntil: holdUntil}, `{"detachedAt":"2024-06-01T00:00:00Z"}`, `{"holdUntil":"2024-06-15T00:00:00Z"}`, nil},
+		{"Other fields are kept", Metadata{DetachedAt: detachedAt}, `{"owner":"billing","holdUntil":"2024-06-15T00:00:00Z"}`, `{"detachedAt":"2024-06-01T00:00:00Z","owner":"billing"}`, nil},
+		{"Empty metadata removes the comment", Metadata{}, `{"holdUntil":"2024-06-15T00:00:00Z"}`, "", nil},
+		{"Empty metadata keeps other fields", Metadata{}, `{"owner":"billing","holdUntil":"2024-06-15T00:00:00Z"}`, `{"owner":"billing"}`, nil},
+		{"Text comment is refused", Metadata{HoldUntil: holdUntil}, "Comment written by a user", "", ErrForeignComment},
+		{"JSON array is refused", Metadata{HoldUntil: holdUntil}, `["billing"]`, "", ErrForeignComment},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			comment, err := tc.metadata.MergeComment(tc.comment)
+			if tc.err != nil {
+				assert.Assert(t, errors.Is(err, tc.err))
+
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, comment, tc.expected)
+		})
+	}
+}
+
+func TestDetachedFromMarker(t *testing.T) {
+	comment, err := Metadata{ParentTable: "public.my_table"}.Encode()
+	assert.NilError(t, err)
+
+	assert.Equal(t, DetachedFromMarker("public.my_table"), `"parentTable":"public.my_table"`)
+	assert.Assert(t, strings.Contains(comment, DetachedFromMarker("public.my_table")))
+}
@@ -0,0 +1,100 @@
+package partition
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxIdentifierLength is the PostgreSQL identifier limit in bytes, longer identifiers are silently truncated
+const MaxIdentifierLength = 63
+
+// Length of the table name hash added to shortened partition names
+const nameHashLength = 8
+
+var ErrIdentifierTooLong = errors.New("identifier exceeds the PostgreSQL limit of 63 bytes")
+
+// partitionName returns the name of a partition of the table, <table>_<suffix>.
+// Names exceeding the PostgreSQL identifier limit are shortened to <truncated table>_<hash>_<suffix>,
+// where hash is derived from the full table name, so tables sharing a long prefix never get the same partition names.
+// The suffix is always kept, so the name still ends with the partition dates.
+func (p Configuration) partitionName(suffix string) string {
+	return shortenIdentifier(p.Table, suffix)
+}
+
+// shortenIdentifier returns <prefix>_<suffix>, shortened to <truncated prefix>_<hash>_<suffix> when it exceeds the identifier limit.
+// The hash is derived from the full prefix. A suffix too long to keep is truncated after the hash.
+func shortenIdentifier(prefix, suffix string) string {
+	name := fmt.Sprintf("%s_%s", prefix, suffix)
+	if len(name) <= MaxIdentifierLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(prefix))
+	hash := hex.EncodeToString(sum[:])[:nameHashLength]
+
+	length := MaxIdentifierLength - len(suffix) - nameHashLength - 2 //nolint:mnd // two underscores
+	if length < 1 {
+		return truncate(fmt.Sprintf("%s_%s", hash, suffix), MaxIdentifierLength)
+	}
+
+	return fmt.Sprintf("%s_%s_%s", truncate(prefix, length), hash, suffix)
+}
+
+// TruncatedIdentifier returns the identifier as stored by PostgreSQL, which silently truncates identifiers exceeding the limit.
+// Tables and indexes created before their names were shortened have such names.
+func TruncatedIdentifier(identifier string) string {
+	return truncate(identifier, MaxIdentifierLength)
+}
+
+// DetachedName returns the name of a partition renamed on detach
+func (p Configuration) DetachedName(name string, detachedAt time.Time) string {
+	return fmt.Sprintf("%s_detached_%s", name, detachedAt.Format("20060102"))
+}
+
+// CheckIdentifiers returns an error when the table name or the generated names exceed the PostgreSQL identifier limit.
+// Partition names are shortened to fit, but detached partition names add a suffix to them.
+func (p Configuration) CheckIdentifiers() error {
+	for _, identifier := range []string{p.Schema, p.Table, p.DetachedSchema} {
+		if len(identifier) > MaxIdentifierLength {
+			return fmt.Errorf("%w: %s", ErrIdentifierTooLong, identifier)
+		}
+	}
+
+	if !p.RenameDetached {
+		return nil
+	}
+
+	// Suffixes have a fixed length for an interval, so any date gives the longest names
+	at := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)
+
+	part, err := p.GeneratePartition(at)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{part.Name, p.SegmentName(at, at)} {
+		detached := p.DetachedName(name, at)
+		if len(detached) > MaxIdentifierLength {
+			return fmt.Errorf("%w: %s", ErrIdentifierTooLong, detached)
+		}
+	}
+
+	return nil
+}
+
+// truncate returns the longest prefix of s within length bytes, without splitting a multi-byte character
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+
+	for length > 0 && !utf8.RuneStart(s[length]) {
+		length--
+	}
+
+	return s[:length]
+}
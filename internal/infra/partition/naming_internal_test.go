@@ -0,0 +1,116 @@
+package partition
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestPartitionNameShortening(t *testing.T) {
+	forDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	short := configForInterval(Daily, 1, 1)
+	part, err := short.GeneratePartition(forDate)
+	assert.NilError(t, err)
+	assert.Equal(t, part.Name, "test_table_2024_06_15", "Names within the limit are not shortened")
+
+	long := configForInterval(Daily, 1, 1)
+	long.Table = strings.Repeat("t", 55) + "_events"
+	part, err = long.GeneratePartition(forDate)
+	assert.NilError(t, err)
+	assert.Equal(t, len(part.Name), MaxIdentifierLength)
+	assert.Assert(t, strings.HasPrefix(part.Name, long.Table[:40]), "Shortened names start with the table name")
+	assert.Assert(t, strings.HasSuffix(part.Name, "_2024_06_15"), "Shortened names keep the partition suffix")
+
+	again, _ := long.GeneratePartition(forDate)
+	assert.Equal(t, again.Name, part.Name, "Shortening is deterministic")
+
+	// Tables sharing a long prefix get different partition names
+	other := long
+	other.Table = strings.Repeat("t", 55) + "_metrics"
+	otherPart, err := other.GeneratePartition(forDate)
+	assert.NilError(t, err)
+	assert.Assert(t, otherPart.Name != part.Name)
+
+	segment := long.SegmentName(forDate, forDate.AddDate(0, 0, 1))
+	assert.Equal(t, len(segment), MaxIdentifierLength)
+	assert.Assert(t, strings.HasSuffix(segment, "_20240615_20240616"))
+}
+
+func TestPartitionNameShorteningMultiByte(t *testing.T) {
+	config := configForInterval(Monthly, 1, 1)
+	config.Table = strings.Repeat("é", 30)
+
+	part, err := config.GeneratePartition(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	assert.NilError(t, err)
+	assert.Assert(t, len(part.Name) <= MaxIdentifierLength)
+	assert.Assert(t, strings.HasSuffix(part.Name, "_2024_06"))
+	assert.Assert(t, strings.ToValidUTF8(part.Name, "?") == part.Name, "Characters are not split")
+}
+
+func TestPartitionIndexName(t *testing.T) {
+	forDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	short := configForInterval(Daily, 1, 1)
+	part, _ := short.GeneratePartition(forDate)
+	assert.Equal(t, PartitionIndexName(part, "brin"), "test_table_2024_06_15_brin", "Names within the limit are not shortened")
+	assert.Equal(t, LegacyPartitionIndexName(part, "brin"), "test_table_2024_06_15_brin")
+
+	// Partition names of long tables already fill the identifier limit
+	long := configForInterval(Daily, 1, 1)
+	long.Table = strings.Repeat("t", 60)
+	part, _ = long.GeneratePartition(forDate)
+	next, _ := long.GeneratePartition(forDate.AddDate(0, 0, 1))
+
+	name := PartitionIndexName(part, "brin")
+	assert.Equal(t, len(name), MaxIdentifierLength)
+	assert.Assert(t, strings.HasSuffix(name, "_brin"), "Shortened names keep the index name")
+	assert.Assert(t, name != PartitionIndexName(next, "brin"), "Partitions sharing a long prefix get different index names")
+	assert.Equal(t, LegacyPartitionIndexName(part, "brin"), part.Name, "PostgreSQL truncated the index name to the partition name")
+
+	longRule := PartitionIndexName(part, strings.Repeat("i", 60))
+	assert.Equal(t, len(longRule), MaxIdentifierLength)
+	assert.Assert(t, longRule != PartitionIndexName(next, strings.Repeat("i", 60)))
+}
+
+func TestGeneratePartitionTableTooLong(t *testing.T) {
+	config := configForInterval(Daily, 1, 1)
+	config.Table = strings.Repeat("t", MaxIdentifierLength+1)
+
+	_, err := config.GeneratePartition(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	assert.Assert(t, errors.Is(err, ErrIdentifierTooLong))
+}
+
+func TestCheckIdentifiers(t *testing.T) {
+	testCases := []struct {
+		name           string
+		table          string
+		interval       Interval
+		renameDetached bool
+		valid          bool
+	}{
+		{"Short table", "test_table", Daily, true, true},
+		{"Long table shortened", strings.Repeat("t", 60), Daily, false, true},
+		{"Table exceeding the limit", strings.Repeat("t", 64), Yearly, false, false},
+		{"Detached name exceeding the limit", strings.Repeat("t", 40), Daily, true, false},
+		{"Detached name within the limit", strings.Repeat("t", 20), Yearly, true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := configForInterval(tc.interval, 1, 1)
+			config.Table = tc.table
+			config.RenameDetached = tc.renameDetached
+
+			err := config.CheckIdentifiers()
+			if tc.valid {
+				assert.NilError(t, err)
+			} else {
+				assert.Assert(t, errors.Is(err, ErrIdentifierTooLong))
+			}
+		})
+	}
+}
@@ -12,6 +12,8 @@ type Partition struct {
 	Name        string
 	LowerBound  time.Time
 	UpperBound  time.Time
+	Metadata    Metadata
+	Comment     string // table comment the metadata was read from, its other fields are kept when the metadata is written
 }
 
 func (p Partition) String() string {
@@ -23,3 +25,8 @@ func (p Partition) String() string {
 func (p Partition) QualifiedName() string {
 	return fmt.Sprintf("%s.%s", p.Schema, p.Name)
 }
+
+// Overlaps returns true when the ranges of both partitions intersect
+func (p Partition) Overlaps(other Partition) bool {
+	return p.LowerBound.Before(other.UpperBound) && other.LowerBound.Before(p.UpperBound)
+}
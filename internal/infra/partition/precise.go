@@ -0,0 +1,99 @@
+package partition
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPreciseRetentionBatchSize  = 10000
+	defaultPreciseRetentionBatchPause = 100 * time.Millisecond
+	daysInALeapCycle                  = 4*365 + 1
+)
+
+var ErrPreciseRetentionOutOfInterval = errors.New("precise retention cutoff is outside of the oldest retained partition")
+
+// PreciseRetentionConfiguration describes the deletion of rows older than an exact number of days from retained partitions
+type PreciseRetentionConfiguration struct {
+	Days       int `mapstructure:"days" validate:"required,gt=0"`
+	BatchSize  int `mapstructure:"batchSize" validate:"omitempty,gt=0"`
+	BatchPause int `mapstructure:"batchPause" validate:"omitempty,gt=0"` // milliseconds
+}
+
+// Cutoff returns the date before which rows are deleted
+func (r PreciseRetentionConfiguration) Cutoff(forDate time.Time) time.Time {
+	year, month, day := forDate.AddDate(0, 0, -r.Days).Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, forDate.Location())
+}
+
+// GetBatchSize returns the maximum number of rows deleted by a statement, 10000 by default
+func (r PreciseRetentionConfiguration) GetBatchSize() int {
+	if r.BatchSize == 0 {
+		return defaultPreciseRetentionBatchSize
+	}
+
+	return r.BatchSize
+}
+
+// GetBatchPause returns the pause between two deletions, 100ms by default
+func (r PreciseRetentionConfiguration) GetBatchPause() time.Duration {
+	if r.BatchPause == 0 {
+		return defaultPreciseRetentionBatchPause
+	}
+
+	return time.Duration(r.BatchPause) * time.Millisecond
+}
+
+// PreciseRetentionCutoff returns the date before which precise retention deletes rows at the given date, and the oldest retained partition.
+// A cutoff after the oldest retained partition is refused, as it would delete rows of newer partitions.
+func (p Configuration) PreciseRetentionCutoff(at time.Time) (cutoff time.Time, oldest Partition, err error) {
+	cutoff = p.PreciseRetention.Cutoff(at)
+
+	prevDate, err := p.getPrevDate(at, p.Retention)
+	if err != nil {
+		return time.Time{}, Partition{}, fmt.Errorf("could not compute previous date: %w", err)
+	}
+
+	oldest, err = p.GeneratePartition(prevDate)
+	if err != nil {
+		return time.Time{}, Partition{}, fmt.Errorf("could not generate partition: %w", err)
+	}
+
+	if cutoff.After(oldest.UpperBound) {
+		return time.Time{}, Partition{}, fmt.Errorf("%w: %d days before %s is %s, after %s", ErrPreciseRetentionOutOfInterval,
+			p.PreciseRetention.Days, at.Format(time.DateOnly), cutoff.Format(time.DateOnly), Bounds(oldest.LowerBound, oldest.UpperBound))
+	}
+
+	return cutoff, oldest, nil
+}
+
+// CheckPreciseRetention checks the precise retention cutoff falls within the oldest retained partition.
+// The cutoff must never be after it, and must be within it at some dates, otherwise precise retention never deletes anything.
+// Interval lengths vary with months and leap years, so every day of a leap cycle is checked.
+func (p Configuration) CheckPreciseRetention() error {
+	if p.PreciseRetention == nil {
+		return nil
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reached := false
+
+	for day := range daysInALeapCycle {
+		cutoff, oldest, err := p.PreciseRetentionCutoff(start.AddDate(0, 0, day))
+		if err != nil {
+			return err
+		}
+
+		if !cutoff.Before(oldest.LowerBound) {
+			reached = true
+		}
+	}
+
+	if !reached {
+		return fmt.Errorf("%w: %d days is always before the oldest retained partition", ErrPreciseRetentionOutOfInterval, p.PreciseRetention.Days)
+	}
+
+	return nil
+}
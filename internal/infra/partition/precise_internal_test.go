@@ -0,0 +1,44 @@
+package partition
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestCheckPreciseRetention(t *testing.T) {
+	testCases := []struct {
+		name      string
+		interval  Interval
+		retention int
+		days      int
+		valid     bool
+	}{
+		{"Within the oldest month", Monthly, 12, 365, true},
+		{"Within the oldest month in leap years", Monthly, 12, 366, true},
+		{"Before the oldest month", Monthly, 12, 400, false},
+		{"Within newer months", Monthly, 12, 300, false},
+		{"Within the oldest day", Daily, 2, 1, true},
+		{"Before the oldest day", Daily, 2, 3, false},
+		{"Within the oldest year", Yearly, 2, 800, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := Configuration{
+				Table:            "my_table",
+				Interval:         tc.interval,
+				Retention:        tc.retention,
+				PreciseRetention: &PreciseRetentionConfiguration{Days: tc.days},
+			}
+
+			err := config.CheckPreciseRetention()
+			if tc.valid {
+				assert.NilError(t, err)
+			} else {
+				assert.Assert(t, errors.Is(err, ErrPreciseRetentionOutOfInterval))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package partition
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidSize = errors.New("invalid size")
+
+var sizeRegexp = regexp.MustCompile(`^\s*([0-9]+)\s*([a-zA-Z]*)\s*$`)
+
+var sizeUnits = map[string]int64{
+	"":   1,
+	"B":  1,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+	"TB": 1 << 40,
+}
+
+// SafeguardConfiguration limits what a single cleanup may remove, to protect data against a wrong work date or retention
+type SafeguardConfiguration struct {
+	CheckRecentRows  bool   `mapstructure:"checkRecentRows"`                              // refuse partitions holding rows newer than the retention cutoff
+	MaxPartitionSize string `mapstructure:"maxPartitionSize" validate:"omitempty,size"`   // refuse partitions larger than this size (e.g. "10GB")
+	MaxPartitions    int    `mapstructure:"maxPartitions" validate:"omitempty,gt=0"`      // refuse runs removing more partitions
+	MaxPercent       int    `mapstructure:"maxPercent" validate:"omitempty,gt=0,lte=100"` // refuse runs removing a larger share of the partitions
+}
+
+// GetMaxPartitionSize returns the maximum partition size in bytes, 0 when unlimited
+func (s SafeguardConfiguration) GetMaxPartitionSize() (int64, error) {
+	if s.MaxPartitionSize == "" {
+		return 0, nil
+	}
+
+	return ParseSize(s.MaxPartitionSize)
+}
+
+// ParseSize converts a size with an optional binary unit (B, kB, MB, GB, TB) into bytes, like pg_size_pretty displays them
+func ParseSize(size string) (int64, error) {
+	matches := sizeRegexp.FindStringSubmatch(size)
+	if matches == nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSize, size)
+	}
+
+	multiplier, found := sizeUnits[strings.ToUpper(matches[2])]
+	if !found {
+		return 0, fmt.Errorf("%w: unknown unit %q", ErrInvalidSize, matches[2])
+	}
+
+	value, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidSize, err)
+	}
+
+	if value > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("%w: %q overflows", ErrInvalidSize, size)
+	}
+
+	return value * multiplier, nil
+}
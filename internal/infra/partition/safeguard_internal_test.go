@@ -0,0 +1,35 @@
+package partition
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	testCases := []struct {
+		size     string
+		expected int64
+	}{
+		{"1024", 1024},
+		{"512B", 512},
+		{"2kB", 2048},
+		{"500 MB", 500 * 1024 * 1024},
+		{"10GB", 10 * 1024 * 1024 * 1024},
+		{"1tb", 1024 * 1024 * 1024 * 1024},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.size, func(t *testing.T) {
+			size, err := ParseSize(tc.size)
+			assert.NilError(t, err)
+			assert.Equal(t, size, tc.expected)
+		})
+	}
+
+	for _, invalid := range []string{"", "GB", "10 PB", "-1MB", "1.5GB", "9223372036854775807kB", "8388608TB"} {
+		_, err := ParseSize(invalid)
+		assert.Assert(t, errors.Is(err, ErrInvalidSize), invalid)
+	}
+}
@@ -0,0 +1,59 @@
+package partition
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetentionTier keeps the first partition of each period past the retention, for grandfather-father-son retention schemes
+type RetentionTier struct {
+	Every     Interval `mapstructure:"every" validate:"required,oneof=weekly monthly quarterly yearly"`
+	Retention int      `mapstructure:"retention" validate:"omitempty,gt=0"` // number of periods kept, forever when not set
+}
+
+// Keeps returns true when the partition is the first partition of a period kept by the tier at the given date
+func (t RetentionTier) Keeps(part Partition, at time.Time) bool {
+	periods := Configuration{Interval: t.Every}
+
+	// The first partition of a period contains the period start
+	period, err := periods.GeneratePartition(part.UpperBound.Add(-time.Nanosecond))
+	if err != nil || part.LowerBound.After(period.LowerBound) {
+		return false
+	}
+
+	if t.Retention == 0 {
+		return true
+	}
+
+	oldestDate, err := periods.getPrevDate(at, t.Retention-1)
+	if err != nil {
+		return false
+	}
+
+	oldest, err := periods.GeneratePartition(oldestDate)
+	if err != nil {
+		return false
+	}
+
+	return !period.LowerBound.Before(oldest.LowerBound)
+}
+
+// IsKept returns true when a hold or a retention tier keeps the partition past the retention at the given date, with the reason
+func (p Configuration) IsKept(part Partition, at time.Time) (kept bool, reason string) {
+	if held, reason := p.GetHold(part, at); held {
+		return true, reason
+	}
+
+	for _, tier := range p.Tiers {
+		if tier.Keeps(part, at) {
+			return true, fmt.Sprintf("%s retention tier", tier.Every)
+		}
+	}
+
+	return false, ""
+}
+
+// AllowsGaps returns true when the partition set may contain gaps on purpose, so cleanup must not require contiguous partitions
+func (p Configuration) AllowsGaps() bool {
+	return p.Sparse || len(p.Tiers) > 0 || p.KeepIf != ""
+}
@@ -0,0 +1,39 @@
+package partition
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestRetentionTiers(t *testing.T) {
+	at := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := Configuration{
+		Tiers: []RetentionTier{
+			{Every: Monthly, Retention: 12},
+			{Every: Yearly},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		part     Partition
+		expected bool
+	}{
+		{"First day of the current month", dailyPartition(2024, 6, 1), true},
+		{"Other day of the month", dailyPartition(2024, 6, 2), false},
+		{"First day of the oldest kept month", dailyPartition(2023, 7, 1), true},
+		{"First day of a month past the tier retention", dailyPartition(2023, 6, 1), false},
+		{"First day of a year", dailyPartition(2020, 1, 1), true},
+		{"Week containing the first day of a month", Partition{LowerBound: date(2024, 1, 29), UpperBound: date(2024, 2, 5)}, true},
+		{"Week after the first day of a month", Partition{LowerBound: date(2024, 2, 5), UpperBound: date(2024, 2, 12)}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kept, _ := config.IsKept(tc.part, at)
+			assert.Equal(t, kept, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package postgresql
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CopyTableTo exports the table content to the writer with COPY TO STDOUT, in csv or binary format
+func (p Postgres) CopyTableTo(schema, table, format string, w io.Writer) (rows int64, err error) {
+	query := fmt.Sprintf("COPY %s TO STDOUT WITH (%s)", pgx.Identifier{schema, table}.Sanitize(), copyOptions(format))
+	p.logger.Debug("Copy table", "schema", schema, "table", table, "query", query)
+
+	tag, err := p.conn.PgConn().CopyTo(p.ctx, w, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy table: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// CopyTableFrom imports rows into the table with COPY FROM STDIN, in csv or binary format
+func (p Postgres) CopyTableFrom(schema, table, format string, r io.Reader) (rows int64, err error) {
+	query := fmt.Sprintf("COPY %s FROM STDIN WITH (%s)", pgx.Identifier{schema, table}.Sanitize(), copyOptions(format))
+	p.logger.Debug("Copy into table", "schema", schema, "table", table, "query", query)
+
+	tag, err := p.conn.PgConn().CopyFrom(p.ctx, r, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy into table: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func copyOptions(format string) string {
+	if format == "csv" {
+		return "FORMAT csv, HEADER true"
+	}
+
+	return "FORMAT binary"
+}
+
+// GetColumnsDefinition returns a CREATE TABLE statement with the names, types and NOT NULL constraints of the table columns.
+// Defaults, constraints and indexes are not included.
+func (p Postgres) GetColumnsDefinition(schema, table string) (definition string, err error) {
+	query := `SELECT format('CREATE TABLE %I.%I (%s)', n.nspname, c.relname,
+			string_agg(format('%I %s%s', a.attname, pg_catalog.format_type(a.atttypid, a.atttypmod),
+				CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END), ', ' ORDER BY a.attnum))
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+		WHERE n.nspname = $1 AND c.relname = $2
+		GROUP BY n.nspname, c.relname`
+
+	err = p.conn.QueryRow(p.ctx, query, schema, table).Scan(&definition)
+	if err != nil {
+		return "", fmt.Errorf("failed to get columns definition: %w", err)
+	}
+
+	return definition, nil
+}
+
+func (p Postgres) CountRows(schema, table string) (count int64, err error) {
+	query := fmt.Sprintf("SELECT count(*) FROM %s", pgx.Identifier{schema, table}.Sanitize())
+
+	err = p.conn.QueryRow(p.ctx, query).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count rows: %w", err)
+	}
+
+	return count, nil
+}
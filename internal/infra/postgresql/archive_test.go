@@ -0,0 +1,42 @@
+//nolint:wsl_v5
+package postgresql_test
+
+import (
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetColumnsDefinition(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT format"
+	definition := `CREATE TABLE public.my_table (id bigint NOT NULL, created_at date NOT NULL)`
+
+	mock.ExpectQuery(query).WithArgs(schema, table).WillReturnRows(mock.NewRows([]string{"format"}).AddRow(definition))
+	result, err := p.GetColumnsDefinition(schema, table)
+	assert.Nil(t, err, "GetColumnsDefinition should succeed")
+	assert.Equal(t, definition, result)
+
+	mock.ExpectQuery(query).WithArgs(schema, table).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.GetColumnsDefinition(schema, table)
+	assert.Error(t, err, "GetColumnsDefinition should fail")
+}
+
+func TestCountRows(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+	query := `SELECT count(*) FROM "public"."my_table"`
+
+	mock.ExpectQuery(query).WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(42)))
+	count, err := p.CountRows(schema, table)
+	assert.Nil(t, err, "CountRows should succeed")
+	assert.Equal(t, int64(42), count)
+
+	mock.ExpectQuery(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.CountRows(schema, table)
+	assert.Error(t, err, "CountRows should fail")
+}
@@ -3,6 +3,9 @@ package postgresql
 import (
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // ErrUnsupportedPartitionKeyType represents an error indicating that the column type for partitioning is not supported.
@@ -48,3 +51,63 @@ func (p Postgres) GetColumnDataType(schema, table, column string) (ColumnType, e
 		return "", fmt.Errorf("%w: %s", ErrUnsupportedPartitionKeyType, columnType)
 	}
 }
+
+// Column describes a column of a table
+type Column struct {
+	Name    string
+	Type    string // type with modifiers, as formatted by format_type (e.g. "character varying(32)")
+	NotNull bool
+}
+
+// ListColumns returns the columns of the table, ordered by name
+func (p Postgres) ListColumns(schema, table string) (columns []Column, err error) {
+	query := `SELECT
+		a.attname AS name,
+		pg_catalog.format_type(a.atttypid, a.atttypmod) AS type,
+		a.attnotnull AS notNull
+	FROM pg_catalog.pg_attribute a
+	JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY a.attname`
+
+	rows, err := p.conn.Query(p.ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+
+	columns, err = pgx.CollectRows(rows, pgx.RowToStructByName[Column])
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast list: %w", err)
+	}
+
+	return columns, nil
+}
+
+// listInsertableColumns returns the sanitized, comma-separated list of the table columns that accept values, in table order.
+// Generated columns are computed by PostgreSQL and cannot be inserted.
+func (p Postgres) listInsertableColumns(schema, table string) (string, error) {
+	query := `SELECT a.attname
+	FROM pg_catalog.pg_attribute a
+	JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped AND a.attgenerated = ''
+	ORDER BY a.attnum`
+
+	rows, err := p.conn.Query(p.ctx, query, schema, table)
+	if err != nil {
+		return "", fmt.Errorf("failed to list insertable columns: %w", err)
+	}
+
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", fmt.Errorf("failed to cast list: %w", err)
+	}
+
+	columns := make([]string, 0, len(names))
+	for _, name := range names {
+		columns = append(columns, pgx.Identifier{name}.Sanitize())
+	}
+
+	return strings.Join(columns, ", "), nil
+}
@@ -0,0 +1,311 @@
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PartitionedIndex describes an index of a partitioned table
+type PartitionedIndex struct {
+	Name       string
+	Definition string // part of the index definition following the table name (e.g. "USING btree (created_at)")
+	Unique     bool
+	Constraint string // constraint using the index ("PRIMARY KEY" or "UNIQUE"), empty for a plain index
+}
+
+// PartitionIndexResult describes an index of a partition.
+// Index is empty when the partition has no index attached to ParentIndex.
+type PartitionIndexResult struct {
+	ParentIndex string
+	Partition   string
+	Index       string
+	Valid       bool
+	Attached    bool
+}
+
+// IndexRename is an index to rename along with its table
+type IndexRename struct {
+	Name    string
+	NewName string
+}
+
+type IndexStatus struct {
+	Exists     bool
+	Valid      bool // false when a concurrent build was interrupted (pg_index.indisvalid)
+	Attached   bool // true when the index is a partition of a partitioned index
+	Constraint bool // true when a primary key or unique constraint uses the index
+}
+
+// CreateIndexConcurrently creates an index without blocking writes on the table.
+// The definition is the part of the CREATE INDEX statement following the table name (e.g. "USING brin (created_at)").
+func (p Postgres) CreateIndexConcurrently(schema, table, index, definition string) error {
+	return p.createIndexConcurrently("INDEX", schema, table, index, definition)
+}
+
+// CreateUniqueIndexConcurrently creates an unique index without blocking writes on the table
+func (p Postgres) CreateUniqueIndexConcurrently(schema, table, index, definition string) error {
+	return p.createIndexConcurrently("UNIQUE INDEX", schema, table, index, definition)
+}
+
+func (p Postgres) createIndexConcurrently(kind, schema, table, index, definition string) error {
+	query := fmt.Sprintf("CREATE %s CONCURRENTLY IF NOT EXISTS %s ON %s %s",
+		kind,
+		pgx.Identifier{index}.Sanitize(),
+		pgx.Identifier{schema, table}.Sanitize(),
+		definition)
+	p.logger.Debug("Create index", "schema", schema, "table", table, "index", index, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+
+	return nil
+}
+
+// DropIndexConcurrently drops an index without blocking writes on its table
+func (p Postgres) DropIndexConcurrently(schema, index string) error {
+	query := fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", pgx.Identifier{schema, index}.Sanitize())
+	p.logger.Debug("Drop index", "schema", schema, "index", index, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to drop index: %w", err)
+	}
+
+	return nil
+}
+
+// RenameIndex renames an index
+func (p Postgres) RenameIndex(schema, index, newName string) error {
+	query := fmt.Sprintf("ALTER INDEX %s RENAME TO %s",
+		pgx.Identifier{schema, index}.Sanitize(),
+		pgx.Identifier{newName}.Sanitize())
+	p.logger.Debug("Rename index", "schema", schema, "index", index, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to rename index: %w", err)
+	}
+
+	return nil
+}
+
+func (p Postgres) GetIndexStatus(schema, index string) (status IndexStatus, err error) {
+	query := `SELECT
+		i.indisvalid,
+		EXISTS (SELECT 1 FROM pg_catalog.pg_inherits inh WHERE inh.inhrelid = c.oid),
+		EXISTS (SELECT 1 FROM pg_catalog.pg_constraint con WHERE con.conindid = c.oid AND con.conrelid = i.indrelid AND con.contype IN ('p', 'u'))
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_catalog.pg_index i ON i.indexrelid = c.oid
+	WHERE n.nspname = $1 AND c.relname = $2`
+
+	err = p.conn.QueryRow(p.ctx, query, schema, index).Scan(&status.Valid, &status.Attached, &status.Constraint)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return IndexStatus{}, nil
+	}
+
+	if err != nil {
+		return IndexStatus{}, fmt.Errorf("failed to get index status: %w", err)
+	}
+
+	status.Exists = true
+
+	return status, nil
+}
+
+// CreateIndexOnParent creates an index on the partitioned table only, without recursing to partitions.
+// The index stays invalid until an index is attached for each partition.
+func (p Postgres) CreateIndexOnParent(schema, table, index, definition string) error {
+	query := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON ONLY %s %s",
+		pgx.Identifier{index}.Sanitize(),
+		pgx.Identifier{schema, table}.Sanitize(),
+		definition)
+	p.logger.Debug("Create index on parent table", "schema", schema, "table", table, "index", index, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to create index on parent table: %w", err)
+	}
+
+	return nil
+}
+
+// AttachIndex attaches a partition index to a partitioned index
+func (p Postgres) AttachIndex(schema, parentIndex, index string) error {
+	query := fmt.Sprintf("ALTER INDEX %s ATTACH PARTITION %s",
+		pgx.Identifier{schema, parentIndex}.Sanitize(),
+		pgx.Identifier{schema, index}.Sanitize())
+	p.logger.Debug("Attach index", "schema", schema, "index", index, "parent_index", parentIndex, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to attach index: %w", err)
+	}
+
+	return nil
+}
+
+// AddIndexConstraint adds a primary key or unique constraint using an existing unique index, without building another index.
+// The constraint takes the name of the index.
+func (p Postgres) AddIndexConstraint(schema, table, index, constraint string) error {
+	query := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s USING INDEX %s",
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{index}.Sanitize(),
+		constraint,
+		pgx.Identifier{index}.Sanitize())
+	p.logger.Debug("Add index constraint", "schema", schema, "table", table, "index", index, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to add index constraint: %w", err)
+	}
+
+	return nil
+}
+
+// IsIndexPartitionAttached returns true when an index of the table is attached to the partitioned index
+func (p Postgres) IsIndexPartitionAttached(schema, parentIndex, table string) (attached bool, err error) {
+	query := `SELECT EXISTS (
+		SELECT 1
+		FROM pg_catalog.pg_inherits inh
+		JOIN pg_catalog.pg_index i ON i.indexrelid = inh.inhrelid
+		JOIN pg_catalog.pg_class t ON t.oid = i.indrelid
+		JOIN pg_catalog.pg_class pi ON pi.oid = inh.inhparent
+		JOIN pg_catalog.pg_namespace n ON n.oid = pi.relnamespace
+		WHERE n.nspname = $1 AND pi.relname = $2 AND t.relname = $3 AND t.relnamespace = n.oid
+	)`
+
+	err = p.conn.QueryRow(p.ctx, query, schema, parentIndex, table).Scan(&attached)
+	if err != nil {
+		return false, fmt.Errorf("failed to check index attachment: %w", err)
+	}
+
+	return attached, nil
+}
+
+// ReindexConcurrently rebuilds an index without blocking writes on its table
+func (p Postgres) ReindexConcurrently(schema, index string) error {
+	query := fmt.Sprintf("REINDEX INDEX CONCURRENTLY %s", pgx.Identifier{schema, index}.Sanitize())
+	p.logger.Debug("Reindex", "schema", schema, "index", index, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to reindex: %w", err)
+	}
+
+	return nil
+}
+
+// ListPartitionedIndexes returns the indexes defined on the partitioned table
+func (p Postgres) ListPartitionedIndexes(schema, table string) (indexes []PartitionedIndex, err error) {
+	return p.listIndexes(schema, table, "p")
+}
+
+// ListTableIndexes returns the indexes defined on a regular table, such as a table to attach as a partition
+func (p Postgres) ListTableIndexes(schema, table string) (indexes []PartitionedIndex, err error) {
+	return p.listIndexes(schema, table, "r")
+}
+
+func (p Postgres) listIndexes(schema, table, relkind string) (indexes []PartitionedIndex, err error) {
+	query := fmt.Sprintf(`SELECT
+		c.relname AS name,
+		substring(pg_catalog.pg_get_indexdef(i.indexrelid) from 'USING .*$') AS definition,
+		i.indisunique AS "unique",
+		coalesce((SELECT CASE con.contype WHEN 'p' THEN 'PRIMARY KEY' ELSE 'UNIQUE' END
+			FROM pg_catalog.pg_constraint con
+			WHERE con.conindid = i.indexrelid AND con.conrelid = i.indrelid AND con.contype IN ('p', 'u')), '') AS "constraint"
+	FROM pg_catalog.pg_index i
+	JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid
+	WHERE i.indrelid = (SELECT c.oid
+		        FROM pg_catalog.pg_class c
+		        JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		        WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind='%s')
+	ORDER BY c.relname`, relkind)
+
+	rows, err := p.conn.Query(p.ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+
+	indexes, err = pgx.CollectRows(rows, pgx.RowToStructByName[PartitionedIndex])
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast list: %w", err)
+	}
+
+	return indexes, nil
+}
+
+// ListPartitionIndexes returns, for each index of the partitioned table and each partition, the attached partition index
+func (p Postgres) ListPartitionIndexes(schema, table string) (indexes []PartitionIndexResult, err error) {
+	query := `WITH parent AS (
+		SELECT c.oid
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind='p'
+	)
+	SELECT
+		pi.relname AS parentIndex,
+		part.relname AS partition,
+		coalesce(ci.relname, '') AS "index",
+		coalesce(cidx.indisvalid, false) AS valid,
+		ci.oid IS NOT NULL AS attached
+	FROM pg_catalog.pg_index pidx
+	JOIN pg_catalog.pg_class pi ON pi.oid = pidx.indexrelid
+	JOIN pg_catalog.pg_inherits tinh ON tinh.inhparent = pidx.indrelid
+	JOIN pg_catalog.pg_class part ON part.oid = tinh.inhrelid AND part.relkind = 'r'
+	LEFT JOIN pg_catalog.pg_inherits iinh ON iinh.inhparent = pidx.indexrelid
+		AND iinh.inhrelid IN (SELECT indexrelid FROM pg_catalog.pg_index WHERE indrelid = part.oid)
+	LEFT JOIN pg_catalog.pg_class ci ON ci.oid = iinh.inhrelid
+	LEFT JOIN pg_catalog.pg_index cidx ON cidx.indexrelid = ci.oid
+	WHERE pidx.indrelid = (SELECT oid FROM parent)
+	ORDER BY pi.relname, part.relname`
+
+	rows, err := p.conn.Query(p.ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partition indexes: %w", err)
+	}
+
+	indexes, err = pgx.CollectRows(rows, pgx.RowToStructByName[PartitionIndexResult])
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast list: %w", err)
+	}
+
+	return indexes, nil
+}
+
+// ListInvalidIndexes returns the invalid indexes of the partitions, attached or not to an index of the partitioned table
+func (p Postgres) ListInvalidIndexes(schema, table string) (indexes []PartitionIndexResult, err error) {
+	query := `SELECT
+		coalesce(pi.relname, '') AS parentIndex,
+		part.relname AS partition,
+		ci.relname AS "index",
+		false AS valid,
+		pi.oid IS NOT NULL AS attached
+	FROM pg_catalog.pg_inherits tinh
+	JOIN pg_catalog.pg_class part ON part.oid = tinh.inhrelid AND part.relkind = 'r'
+	JOIN pg_catalog.pg_index cidx ON cidx.indrelid = part.oid AND NOT cidx.indisvalid
+	JOIN pg_catalog.pg_class ci ON ci.oid = cidx.indexrelid
+	LEFT JOIN pg_catalog.pg_inherits iinh ON iinh.inhrelid = ci.oid
+	LEFT JOIN pg_catalog.pg_class pi ON pi.oid = iinh.inhparent
+	WHERE tinh.inhparent = (SELECT c.oid
+		        FROM pg_catalog.pg_class c
+		        JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		        WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind='p')
+	ORDER BY part.relname, ci.relname`
+
+	rows, err := p.conn.Query(p.ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invalid indexes: %w", err)
+	}
+
+	indexes, err = pgx.CollectRows(rows, pgx.RowToStructByName[PartitionIndexResult])
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast list: %w", err)
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,247 @@
+//nolint:wsl_v5
+package postgresql_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIndexConcurrently(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+	query := `CREATE INDEX CONCURRENTLY IF NOT EXISTS "my_table_brin" ON "public"."my_table" USING brin (created_at)`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	err := p.CreateIndexConcurrently(schema, table, "my_table_brin", "USING brin (created_at)")
+	assert.Nil(t, err, "CreateIndexConcurrently should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.CreateIndexConcurrently(schema, table, "my_table_brin", "USING brin (created_at)")
+	assert.Error(t, err, "CreateIndexConcurrently should fail")
+}
+
+func TestDropIndexConcurrently(t *testing.T) {
+	schema, _, _, _ := generateTable(t)
+	query := `DROP INDEX CONCURRENTLY IF EXISTS "public"."my_table_brin"`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("DROP", 0))
+	err := p.DropIndexConcurrently(schema, "my_table_brin")
+	assert.Nil(t, err, "DropIndexConcurrently should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.DropIndexConcurrently(schema, "my_table_brin")
+	assert.Error(t, err, "DropIndexConcurrently should fail")
+}
+
+func TestRenameIndex(t *testing.T) {
+	query := `ALTER INDEX "public"."my_table_2024_06_15_brin" RENAME TO "my_table_2024_06_15_0f3c9a2b_brin"`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.RenameIndex(testSchema, "my_table_2024_06_15_brin", "my_table_2024_06_15_0f3c9a2b_brin")
+	assert.Nil(t, err, "RenameIndex should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.RenameIndex(testSchema, "my_table_2024_06_15_brin", "my_table_2024_06_15_0f3c9a2b_brin")
+	assert.Error(t, err, "RenameIndex should fail")
+}
+
+func TestGetIndexStatus(t *testing.T) {
+	schema, _, _, _ := generateTable(t)
+	index := "my_table_brin"
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT"
+
+	mock.ExpectQuery(query).WithArgs(schema, index).WillReturnRows(mock.NewRows([]string{"indisvalid", "attached", "constraint"}).AddRow(false, true, false))
+	status, err := p.GetIndexStatus(schema, index)
+	assert.Nil(t, err, "GetIndexStatus should succeed")
+	assert.Equal(t, postgresql.IndexStatus{Exists: true, Valid: false, Attached: true}, status)
+
+	mock.ExpectQuery(query).WithArgs(schema, index).WillReturnError(pgx.ErrNoRows)
+	status, err = p.GetIndexStatus(schema, index)
+	assert.Nil(t, err, "GetIndexStatus should succeed for missing index")
+	assert.False(t, status.Exists, "Index should not exist")
+
+	mock.ExpectQuery(query).WithArgs(schema, index).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.GetIndexStatus(schema, index)
+	assert.Error(t, err, "GetIndexStatus should fail")
+}
+
+func TestCreateIndexOnParent(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+	query := `CREATE INDEX IF NOT EXISTS "my_table_idx" ON ONLY "public"."my_table" (customer_id)`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	err := p.CreateIndexOnParent(schema, table, "my_table_idx", "(customer_id)")
+	assert.Nil(t, err, "CreateIndexOnParent should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.CreateIndexOnParent(schema, table, "my_table_idx", "(customer_id)")
+	assert.Error(t, err, "CreateIndexOnParent should fail")
+}
+
+func TestAttachIndex(t *testing.T) {
+	schema, _, _, _ := generateTable(t)
+	query := `ALTER INDEX "public"."my_table_idx" ATTACH PARTITION "public"."my_table_2024_01_01_my_table_idx"`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.AttachIndex(schema, "my_table_idx", "my_table_2024_01_01_my_table_idx")
+	assert.Nil(t, err, "AttachIndex should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.AttachIndex(schema, "my_table_idx", "my_table_2024_01_01_my_table_idx")
+	assert.Error(t, err, "AttachIndex should fail")
+}
+
+func TestIsIndexPartitionAttached(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT EXISTS"
+
+	mock.ExpectQuery(query).WithArgs(schema, "my_table_idx", table).WillReturnRows(mock.NewRows([]string{"EXISTS"}).AddRow(true))
+	attached, err := p.IsIndexPartitionAttached(schema, "my_table_idx", table)
+	assert.Nil(t, err, "IsIndexPartitionAttached should succeed")
+	assert.True(t, attached, "Index should be attached")
+
+	mock.ExpectQuery(query).WithArgs(schema, "my_table_idx", table).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.IsIndexPartitionAttached(schema, "my_table_idx", table)
+	assert.Error(t, err, "IsIndexPartitionAttached should fail")
+}
+
+func TestAddIndexConstraint(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+	query := `ALTER TABLE "public"."my_table" ADD CONSTRAINT "my_table_pkey" PRIMARY KEY USING INDEX "my_table_pkey"`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.AddIndexConstraint(schema, table, "my_table_pkey", "PRIMARY KEY")
+	assert.Nil(t, err, "AddIndexConstraint should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.AddIndexConstraint(schema, table, "my_table_pkey", "PRIMARY KEY")
+	assert.Error(t, err, "AddIndexConstraint should fail")
+}
+
+func TestCreateUniqueIndexConcurrently(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+	query := `CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS "my_table_id" ON "public"."my_table" USING btree (id, created_at)`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	err := p.CreateUniqueIndexConcurrently(schema, table, "my_table_id", "USING btree (id, created_at)")
+	assert.Nil(t, err, "CreateUniqueIndexConcurrently should succeed")
+}
+
+func TestReindexConcurrently(t *testing.T) {
+	schema, _, _, _ := generateTable(t)
+	query := `REINDEX INDEX CONCURRENTLY "public"."my_table_idx"`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("REINDEX", 0))
+	err := p.ReindexConcurrently(schema, "my_table_idx")
+	assert.Nil(t, err, "ReindexConcurrently should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.ReindexConcurrently(schema, "my_table_idx")
+	assert.Error(t, err, "ReindexConcurrently should fail")
+}
+
+func TestListPartitionedIndexes(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT"
+
+	expected := []postgresql.PartitionedIndex{
+		{Name: "my_table_pkey", Definition: "USING btree (id, created_at)", Unique: true, Constraint: "PRIMARY KEY"},
+		{Name: "my_table_status", Definition: "USING btree (status)", Unique: false},
+	}
+
+	rows := mock.NewRows([]string{"name", "definition", "unique", "constraint"})
+	for _, i := range expected {
+		rows.AddRow(i.Name, i.Definition, i.Unique, i.Constraint)
+	}
+
+	mock.ExpectQuery(query).WithArgs(schema, table).WillReturnRows(rows)
+	indexes, err := p.ListPartitionedIndexes(schema, table)
+	assert.Nil(t, err, "ListPartitionedIndexes should succeed")
+	assert.Equal(t, expected, indexes)
+
+	mock.ExpectQuery(query).WithArgs(schema, table).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.ListPartitionedIndexes(schema, table)
+	assert.Error(t, err, "ListPartitionedIndexes should fail")
+}
+
+func TestListPartitionIndexes(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	expected := []postgresql.PartitionIndexResult{
+		{ParentIndex: "my_table_idx", Partition: "my_table_2024_01_01", Index: "my_table_2024_01_01_idx", Valid: true, Attached: true},
+		{ParentIndex: "my_table_idx", Partition: "my_table_2024_01_02", Index: "", Valid: false, Attached: false},
+	}
+
+	for _, name := range []string{"ListPartitionIndexes", "ListInvalidIndexes"} {
+		t.Run(name, func(t *testing.T) {
+			mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+
+			list := p.ListPartitionIndexes
+			if name == "ListInvalidIndexes" {
+				list = p.ListInvalidIndexes
+			}
+
+			rows := mock.NewRows([]string{"parentIndex", "partition", "index", "valid", "attached"})
+			for _, i := range expected {
+				rows.AddRow(i.ParentIndex, i.Partition, i.Index, i.Valid, i.Attached)
+			}
+
+			mock.ExpectQuery("SELECT").WithArgs(schema, table).WillReturnRows(rows)
+			indexes, err := list(schema, table)
+			assert.Nil(t, err, "listing should succeed")
+			assert.Equal(t, expected, indexes)
+
+			mock.ExpectQuery("SELECT").WithArgs(schema, table).WillReturnError(ErrPostgreSQLConnectionFailure)
+			_, err = list(schema, table)
+			assert.Error(t, err, "listing should fail")
+		})
+	}
+}
+
+func TestListTableIndexes(t *testing.T) {
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT .* c.relkind='r'"
+
+	expected := []postgresql.PartitionedIndex{
+		{Name: "my_table_new_pkey", Definition: "USING btree (id, created_at)", Unique: true, Constraint: "PRIMARY KEY"},
+	}
+
+	rows := mock.NewRows([]string{"name", "definition", "unique", "constraint"})
+	for _, i := range expected {
+		rows.AddRow(i.Name, i.Definition, i.Unique, i.Constraint)
+	}
+
+	mock.ExpectQuery(query).WithArgs(testSchema, "my_table_new").WillReturnRows(rows)
+	indexes, err := p.ListTableIndexes(testSchema, "my_table_new")
+	assert.Nil(t, err, "ListTableIndexes should succeed")
+	assert.Equal(t, expected, indexes)
+
+	mock.ExpectQuery(query).WithArgs(testSchema, "my_table_new").WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.ListTableIndexes(testSchema, "my_table_new")
+	assert.Error(t, err, "ListTableIndexes should fail")
+}
@@ -21,6 +21,7 @@ type PartitionResult struct {
 	Name        string
 	LowerBound  string
 	UpperBound  string
+	Comment     string
 }
 
 func (p Postgres) IsPartitionAttached(schema, table string) (exists bool, err error) {
@@ -88,12 +89,13 @@ func (p Postgres) FinalizePartitionDetach(schema, table, parent string) error {
 	return nil
 }
 
-func (p Postgres) ListPartitions(schema, table string) (partitions []PartitionResult, err error) {
-	query := `
+// listPartitionsQuery lists the partitions of a table, completed by an additional condition on the partitions
+const listPartitionsQuery = `
 	WITH parts as (
 		SELECT
 		   n.nspname as schema,
 		   c.relname AS part_name,
+		   coalesce(pg_catalog.obj_description(c.oid, 'pg_class'), '') AS comment,
 		   regexp_match(pg_get_expr(c.relpartbound, c.oid),
 					  'FOR VALUES FROM \(''(.*)''\) TO \(''(.*)''\)') AS bounds
 		 FROM
@@ -105,16 +107,21 @@ func (p Postgres) ListPartitions(schema, table string) (partitions []PartitionRe
                        WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind='p' -- parent
                    )
 		   AND c.relkind='r'
+		   %s
 	)
 	SELECT
 		schema,
 		part_name as name,
 		$2 as parentTable,
 		bounds[1]::text AS lowerBound,
-		bounds[2]::text AS upperBound
+		bounds[2]::text AS upperBound,
+		comment
 	FROM parts
 	ORDER BY part_name`
 
+func (p Postgres) ListPartitions(schema, table string) (partitions []PartitionResult, err error) {
+	query := fmt.Sprintf(listPartitionsQuery, "")
+
 	rows, err := p.conn.Query(p.ctx, query, schema, table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list partitions: %w", err)
@@ -128,6 +135,24 @@ func (p Postgres) ListPartitions(schema, table string) (partitions []PartitionRe
 	return partitions, nil
 }
 
+// ListBoundedPartitions lists the partitions of the table except the default partition, which has no bounds.
+// It is meant for commands handling the default partition on their own, others refuse tables with a default partition.
+func (p Postgres) ListBoundedPartitions(schema, table string) (partitions []PartitionResult, err error) {
+	query := fmt.Sprintf(listPartitionsQuery, "AND pg_get_expr(c.relpartbound, c.oid) <> 'DEFAULT'")
+
+	rows, err := p.conn.Query(p.ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	partitions, err = pgx.CollectRows(rows, pgx.RowToStructByName[PartitionResult])
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast list: %w", err)
+	}
+
+	return partitions, nil
+}
+
 func (p Postgres) GetPartitionSettings(schema, table string) (strategy, key string, err error) {
 	var partkeydef []string
 
@@ -215,3 +240,92 @@ SELECT c_idx_child.relname
 
 	return nil
 }
+
+// GetDefaultPartition returns the default partition of the table, an empty name when the table has no default partition
+func (p Postgres) GetDefaultPartition(schema, table string) (defaultSchema, defaultTable string, err error) {
+	query := `SELECT dn.nspname, d.relname
+		FROM pg_catalog.pg_partitioned_table pt
+		JOIN pg_catalog.pg_class c ON c.oid = pt.partrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_catalog.pg_class d ON d.oid = pt.partdefid
+		JOIN pg_catalog.pg_namespace dn ON dn.oid = d.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2`
+
+	err = p.conn.QueryRow(p.ctx, query, schema, table).Scan(&defaultSchema, &defaultTable)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", "", nil
+	}
+
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get default partition: %w", err)
+	}
+
+	return defaultSchema, defaultTable, nil
+}
+
+// Attachment is a table to attach as a partition for the values in [LowerBound, UpperBound)
+type Attachment struct {
+	Table      string
+	LowerBound string
+	UpperBound string
+}
+
+// ReplacePartitions detaches the partitions and attaches the tables in their place, in a single transaction,
+// so queries on the parent table never miss the rows of the range.
+func (p Postgres) ReplacePartitions(schema, parent string, partitions []string, attachments []Attachment) error {
+	statements := make([]string, 0, len(partitions)+len(attachments))
+
+	for _, partition := range partitions {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s",
+			pgx.Identifier{schema, parent}.Sanitize(),
+			pgx.Identifier{schema, partition}.Sanitize()))
+	}
+
+	for _, attachment := range attachments {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')",
+			pgx.Identifier{schema, parent}.Sanitize(),
+			pgx.Identifier{schema, attachment.Table}.Sanitize(),
+			attachment.LowerBound, attachment.UpperBound))
+	}
+
+	query := joinStatements(statements...)
+	p.logger.Debug("Replace partitions", "schema", schema, "query", query, "parent_table", parent)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to replace partitions: %w", err)
+	}
+
+	return nil
+}
+
+// AttachPartitionWithDefaultRows moves the rows of the default partition within the bounds to the table and attaches it, in a single transaction.
+// The default partition is locked first, so no row of the range can be written to it before the table is attached.
+func (p Postgres) AttachPartitionWithDefaultRows(schema, table, parent, defaultSchema, defaultTable, column, lowerBound, upperBound string) error {
+	columns, err := p.listInsertableColumns(schema, table)
+	if err != nil {
+		return err
+	}
+
+	query := joinStatements(
+		fmt.Sprintf("LOCK TABLE %s IN ACCESS EXCLUSIVE MODE",
+			pgx.Identifier{defaultSchema, defaultTable}.Sanitize()),
+		fmt.Sprintf("WITH moved AS (DELETE FROM %[1]s WHERE %[2]s >= '%[3]s' AND %[2]s < '%[4]s' RETURNING *) INSERT INTO %[5]s (%[6]s) OVERRIDING SYSTEM VALUE SELECT %[6]s FROM moved",
+			pgx.Identifier{defaultSchema, defaultTable}.Sanitize(),
+			pgx.Identifier{column}.Sanitize(),
+			lowerBound, upperBound,
+			pgx.Identifier{schema, table}.Sanitize(),
+			columns),
+		fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')",
+			pgx.Identifier{schema, parent}.Sanitize(),
+			pgx.Identifier{schema, table}.Sanitize(),
+			lowerBound, upperBound))
+	p.logger.Debug("Attach partition with default partition rows", "schema", schema, "table", table, "query", query, "parent_table", parent)
+
+	_, err = p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to attach partition with default partition rows: %w", err)
+	}
+
+	return nil
+}
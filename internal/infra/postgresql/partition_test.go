@@ -143,12 +143,13 @@ func TestListPartitions(t *testing.T) {
 			Name:        fmt.Sprintf("%s_%s", parent, "2024_01_30"),
 			LowerBound:  "2024-01-30",
 			UpperBound:  "2024-01-31",
+			Comment:     `{"holdUntil":"2024-02-15T00:00:00Z"}`,
 		},
 	}
 
-	rows := mock.NewRows([]string{"schema", "name", "parentTable", "lowerBound", "upperBound"})
+	rows := mock.NewRows([]string{"schema", "name", "parentTable", "lowerBound", "upperBound", "comment"})
 	for _, p := range expectedPartitions {
-		rows.AddRow(p.Schema, p.Name, p.ParentTable, p.LowerBound, p.UpperBound)
+		rows.AddRow(p.Schema, p.Name, p.ParentTable, p.LowerBound, p.UpperBound, p.Comment)
 	}
 	mock.ExpectQuery(query).WithArgs(schema, parent).WillReturnRows(rows)
 	result, err := p.ListPartitions(schema, parent)
@@ -164,3 +165,109 @@ func TestListPartitions(t *testing.T) {
 	_, err = p.ListPartitions(schema, parent)
 	assert.Error(t, err, "ListPartitions should fail")
 }
+
+func TestListBoundedPartitions(t *testing.T) {
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := `<> 'DEFAULT'`
+
+	rows := mock.NewRows([]string{"schema", "name", "parentTable", "lowerBound", "upperBound", "comment"}).
+		AddRow(testSchema, "my_table_2024_01_29", testTable, "2024-01-29", "2024-01-30", "")
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnRows(rows)
+	result, err := p.ListBoundedPartitions(testSchema, testTable)
+	assert.Nil(t, err, "ListBoundedPartitions should succeed")
+	assert.Equal(t, len(result), 1)
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.ListBoundedPartitions(testSchema, testTable)
+	assert.Error(t, err, "ListBoundedPartitions should fail")
+}
+
+func TestGetDefaultPartition(t *testing.T) {
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := `SELECT dn.nspname, d.relname`
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnRows(mock.NewRows([]string{"nspname", "relname"}).AddRow(testSchema, "my_table_default"))
+	schema, table, err := p.GetDefaultPartition(testSchema, testTable)
+	assert.Nil(t, err, "GetDefaultPartition should succeed")
+	assert.Equal(t, testSchema, schema)
+	assert.Equal(t, "my_table_default", table)
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnRows(mock.NewRows([]string{"nspname", "relname"}))
+	_, table, err = p.GetDefaultPartition(testSchema, testTable)
+	assert.Nil(t, err, "GetDefaultPartition should succeed without default partition")
+	assert.Empty(t, table)
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, _, err = p.GetDefaultPartition(testSchema, testTable)
+	assert.Error(t, err, "GetDefaultPartition should fail")
+}
+
+func TestReplacePartitions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		partitions  []string
+		attachments []postgresql.Attachment
+		query       string
+	}{
+		{
+			"Merge",
+			[]string{"my_table_2025_03_01", "my_table_2025_03_02"},
+			[]postgresql.Attachment{{Table: "my_table_2025_03", LowerBound: "2025-03-01", UpperBound: "2025-03-03"}},
+			`ALTER TABLE "public"."my_table" DETACH PARTITION "public"."my_table_2025_03_01"; ` +
+				`ALTER TABLE "public"."my_table" DETACH PARTITION "public"."my_table_2025_03_02"; ` +
+				`ALTER TABLE "public"."my_table" ATTACH PARTITION "public"."my_table_2025_03" FOR VALUES FROM ('2025-03-01') TO ('2025-03-03')`,
+		},
+		{
+			"Split",
+			[]string{"my_table_2025_03"},
+			[]postgresql.Attachment{
+				{Table: "my_table_2025_03_01", LowerBound: "2025-03-01", UpperBound: "2025-03-02"},
+				{Table: "my_table_2025_03_02", LowerBound: "2025-03-02", UpperBound: "2025-03-03"},
+			},
+			`ALTER TABLE "public"."my_table" DETACH PARTITION "public"."my_table_2025_03"; ` +
+				`ALTER TABLE "public"."my_table" ATTACH PARTITION "public"."my_table_2025_03_01" FOR VALUES FROM ('2025-03-01') TO ('2025-03-02'); ` +
+				`ALTER TABLE "public"."my_table" ATTACH PARTITION "public"."my_table_2025_03_02" FOR VALUES FROM ('2025-03-02') TO ('2025-03-03')`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+			mock.ExpectExec(tc.query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+			err := p.ReplacePartitions(testSchema, testTable, tc.partitions, tc.attachments)
+			assert.Nil(t, err, "ReplacePartitions should succeed")
+
+			mock.ExpectExec(tc.query).WillReturnError(ErrPostgreSQLConnectionFailure)
+			err = p.ReplacePartitions(testSchema, testTable, tc.partitions, tc.attachments)
+			assert.Error(t, err, "ReplacePartitions should fail")
+		})
+	}
+}
+
+func TestAttachPartitionWithDefaultRows(t *testing.T) {
+	columnsQuery := `SELECT a.attname FROM pg_catalog.pg_attribute a JOIN pg_catalog.pg_class c ON c.oid = a.attrelid JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+		`WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped AND a.attgenerated = '' ORDER BY a.attnum`
+	query := `LOCK TABLE "public"."my_table_default" IN ACCESS EXCLUSIVE MODE; ` +
+		`WITH moved AS (DELETE FROM "public"."my_table_default" WHERE "created_at" >= '2024-06-15' AND "created_at" < '2024-06-16' RETURNING *) ` +
+		`INSERT INTO "public"."my_table_2024_06_15" ("id", "created_at") OVERRIDING SYSTEM VALUE SELECT "id", "created_at" FROM moved; ` +
+		`ALTER TABLE "public"."my_table" ATTACH PARTITION "public"."my_table_2024_06_15" FOR VALUES FROM ('2024-06-15') TO ('2024-06-16')`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	columns := func() *pgxmock.Rows { return mock.NewRows([]string{"attname"}).AddRow("id").AddRow("created_at") }
+
+	mock.ExpectQuery(columnsQuery).WithArgs(testSchema, "my_table_2024_06_15").WillReturnRows(columns())
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.AttachPartitionWithDefaultRows(testSchema, "my_table_2024_06_15", testTable, testSchema, "my_table_default", "created_at", "2024-06-15", "2024-06-16")
+	assert.Nil(t, err, "AttachPartitionWithDefaultRows should succeed")
+
+	mock.ExpectQuery(columnsQuery).WithArgs(testSchema, "my_table_2024_06_15").WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.AttachPartitionWithDefaultRows(testSchema, "my_table_2024_06_15", testTable, testSchema, "my_table_default", "created_at", "2024-06-15", "2024-06-16")
+	assert.Error(t, err, "AttachPartitionWithDefaultRows should fail when columns cannot be listed")
+
+	mock.ExpectQuery(columnsQuery).WithArgs(testSchema, "my_table_2024_06_15").WillReturnRows(columns())
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.AttachPartitionWithDefaultRows(testSchema, "my_table_2024_06_15", testTable, testSchema, "my_table_default", "created_at", "2024-06-15", "2024-06-16")
+	assert.Error(t, err, "AttachPartitionWithDefaultRows should fail")
+}
@@ -4,6 +4,7 @@ package postgresql
 import (
 	"context"
 	"log/slog"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -30,3 +31,9 @@ func New(logger slog.Logger, conn PgxIface) *Postgres {
 		logger: logger,
 	}
 }
+
+// joinStatements builds a query running the statements in a single transaction.
+// Statements sent together without parameters run in an implicit transaction, rolled back when one of them fails.
+func joinStatements(statements ...string) string {
+	return strings.Join(statements, "; ")
+}
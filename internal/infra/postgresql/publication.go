@@ -0,0 +1,55 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AddTableToPublication adds the table to a publication, so logical replication consumers receive its changes
+func (p Postgres) AddTableToPublication(publication, schema, table string) error {
+	query := fmt.Sprintf("ALTER PUBLICATION %s ADD TABLE %s",
+		pgx.Identifier{publication}.Sanitize(),
+		pgx.Identifier{schema, table}.Sanitize())
+	p.logger.Debug("Add table to publication", "schema", schema, "table", table, "publication", publication, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to add table to publication: %w", err)
+	}
+
+	return nil
+}
+
+func (p Postgres) DropTableFromPublication(publication, schema, table string) error {
+	query := fmt.Sprintf("ALTER PUBLICATION %s DROP TABLE %s",
+		pgx.Identifier{publication}.Sanitize(),
+		pgx.Identifier{schema, table}.Sanitize())
+	p.logger.Debug("Drop table from publication", "schema", schema, "table", table, "publication", publication, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to drop table from publication: %w", err)
+	}
+
+	return nil
+}
+
+// IsTableInPublication returns true when the table has been explicitly added to the publication
+func (p Postgres) IsTableInPublication(publication, schema, table string) (member bool, err error) {
+	query := `SELECT EXISTS(
+		        SELECT 1
+		        FROM pg_catalog.pg_publication_rel pr
+		        JOIN pg_catalog.pg_publication pub ON pub.oid = pr.prpubid
+		        JOIN pg_catalog.pg_class c ON c.oid = pr.prrelid
+		        JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		        WHERE pub.pubname = $1 AND n.nspname = $2 AND c.relname = $3
+			);`
+
+	err = p.conn.QueryRow(p.ctx, query, publication, schema, table).Scan(&member)
+	if err != nil {
+		return false, fmt.Errorf("failed to check publication membership: %w", err)
+	}
+
+	return member, nil
+}
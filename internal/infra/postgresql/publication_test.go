@@ -0,0 +1,55 @@
+//nolint:wsl_v5
+package postgresql_test
+
+import (
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTableToPublication(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+	query := `ALTER PUBLICATION "my_publication" ADD TABLE "public"."my_table"`
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.AddTableToPublication("my_publication", schema, table)
+	assert.Nil(t, err, "AddTableToPublication should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.AddTableToPublication("my_publication", schema, table)
+	assert.Error(t, err, "AddTableToPublication should fail")
+}
+
+func TestDropTableFromPublication(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+	query := `ALTER PUBLICATION "my_publication" DROP TABLE "public"."my_table"`
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.DropTableFromPublication("my_publication", schema, table)
+	assert.Nil(t, err, "DropTableFromPublication should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.DropTableFromPublication("my_publication", schema, table)
+	assert.Error(t, err, "DropTableFromPublication should fail")
+}
+
+func TestIsTableInPublication(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT EXISTS"
+
+	mock.ExpectQuery(query).WithArgs("my_publication", schema, table).WillReturnRows(mock.NewRows([]string{"EXISTS"}).AddRow(true))
+	member, err := p.IsTableInPublication("my_publication", schema, table)
+	assert.Nil(t, err, "IsTableInPublication should succeed")
+	assert.True(t, member, "Table should be in publication")
+
+	mock.ExpectQuery(query).WithArgs("my_publication", schema, table).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.IsTableInPublication("my_publication", schema, table)
+	assert.Error(t, err, "IsTableInPublication should fail")
+}
@@ -0,0 +1,52 @@
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrUnsupportedParameter = errors.New("unsupported query parameter")
+
+var parameterRegexp = regexp.MustCompile(`\$([0-9]+)`)
+
+// EvaluateQuery runs a query returning a single value, with the partition bounds as $1 and $2.
+// Only the parameters used by the query are bound, so a query may use $1 only.
+// The value is nil when the query returns no row.
+func (p Postgres) EvaluateQuery(query string, lowerBound, upperBound time.Time) (value any, err error) {
+	args, err := queryArguments(query, lowerBound, upperBound)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.conn.QueryRow(p.ctx, query, args...).Scan(&value)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate query: %w", err)
+	}
+
+	return value, nil
+}
+
+// queryArguments returns the arguments up to the highest parameter used by the query
+func queryArguments(query string, arguments ...any) ([]any, error) {
+	used := 0
+
+	for _, match := range parameterRegexp.FindAllStringSubmatch(query, -1) {
+		position, err := strconv.Atoi(match[1])
+		if err != nil || position < 1 || position > len(arguments) {
+			return nil, fmt.Errorf("%w: %s, only $1 to $%d are supported", ErrUnsupportedParameter, match[0], len(arguments))
+		}
+
+		used = max(used, position)
+	}
+
+	return arguments[:used], nil
+}
@@ -0,0 +1,70 @@
+//nolint:wsl_v5
+package postgresql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateQuery(t *testing.T) {
+	query := "SELECT NOT EXISTS (SELECT 1 FROM exports WHERE period_start = $1::date)"
+	lowerBound := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	upperBound := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	// Only the parameters used by the query are bound
+	mock.ExpectQuery(query).WithArgs(lowerBound).WillReturnRows(mock.NewRows([]string{"not_exported"}).AddRow(true))
+	value, err := p.EvaluateQuery(query, lowerBound, upperBound)
+	assert.Nil(t, err, "EvaluateQuery should succeed")
+	assert.Equal(t, true, value)
+
+	mock.ExpectQuery(query).WithArgs(lowerBound).WillReturnRows(mock.NewRows([]string{"not_exported"}))
+	value, err = p.EvaluateQuery(query, lowerBound, upperBound)
+	assert.Nil(t, err, "EvaluateQuery should succeed without row")
+	assert.Nil(t, value)
+
+	mock.ExpectQuery(query).WithArgs(lowerBound).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.EvaluateQuery(query, lowerBound, upperBound)
+	assert.Error(t, err, "EvaluateQuery should fail")
+}
+
+func TestEvaluateQueryParameters(t *testing.T) {
+	lowerBound := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	upperBound := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		query string
+		args  []any
+	}{
+		{"SELECT exported FROM etl.export_status WHERE period = $1::date", []any{lowerBound}},
+		{"SELECT count(*) = 0 FROM invoices WHERE issued_at >= $1 AND issued_at < $2", []any{lowerBound, upperBound}},
+		{"SELECT count(*) = 0 FROM invoices WHERE issued_at < $2", []any{lowerBound, upperBound}},
+		{"SELECT true", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+			expected := mock.ExpectQuery(tc.query)
+			if tc.args != nil {
+				expected = expected.WithArgs(tc.args...)
+			}
+
+			expected.WillReturnRows(mock.NewRows([]string{"result"}).AddRow(true))
+			_, err := p.EvaluateQuery(tc.query, lowerBound, upperBound)
+			assert.Nil(t, err, "EvaluateQuery should succeed")
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+	_, err := p.EvaluateQuery("SELECT $3", lowerBound, upperBound)
+	assert.ErrorIs(t, err, postgresql.ErrUnsupportedParameter)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
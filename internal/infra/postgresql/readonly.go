@@ -0,0 +1,77 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	readOnlyGuardFunction   = "ppm_read_only_guard"
+	readOnlyTrigger         = "ppm_read_only"
+	readOnlyTruncateTrigger = "ppm_read_only_truncate"
+)
+
+// SetTableReadOnly attaches guard triggers rejecting any INSERT, UPDATE, DELETE and TRUNCATE on the table.
+// Row-level triggers are used since statement-level triggers are not fired for rows routed through the parent table.
+// The function and both triggers are created in a single transaction, so a table is never left with only one trigger.
+func (p Postgres) SetTableReadOnly(schema, table string) error {
+	queryFunction := fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	RAISE EXCEPTION 'table %%.%% is read-only', TG_TABLE_SCHEMA, TG_TABLE_NAME USING ERRCODE = 'read_only_sql_transaction';
+END;
+$$`, pgx.Identifier{schema, readOnlyGuardFunction}.Sanitize())
+
+	queryRowTrigger := fmt.Sprintf("CREATE OR REPLACE TRIGGER %s BEFORE INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE FUNCTION %s()",
+		pgx.Identifier{readOnlyTrigger}.Sanitize(),
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{schema, readOnlyGuardFunction}.Sanitize())
+
+	queryTruncateTrigger := fmt.Sprintf("CREATE OR REPLACE TRIGGER %s BEFORE TRUNCATE ON %s FOR EACH STATEMENT EXECUTE FUNCTION %s()",
+		pgx.Identifier{readOnlyTruncateTrigger}.Sanitize(),
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{schema, readOnlyGuardFunction}.Sanitize())
+
+	query := joinStatements(queryFunction, queryRowTrigger, queryTruncateTrigger)
+	p.logger.Debug("Set table read-only", "schema", schema, "table", table, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to create read-only triggers: %w", err)
+	}
+
+	return nil
+}
+
+// UnsetTableReadOnly removes the guard triggers created by SetTableReadOnly
+func (p Postgres) UnsetTableReadOnly(schema, table string) error {
+	for _, trigger := range []string{readOnlyTrigger, readOnlyTruncateTrigger} {
+		query := fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s",
+			pgx.Identifier{trigger}.Sanitize(),
+			pgx.Identifier{schema, table}.Sanitize())
+		p.logger.Debug("Drop read-only trigger", "schema", schema, "table", table, "query", query)
+
+		_, err := p.conn.Exec(p.ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to drop read-only trigger: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// IsTableReadOnly returns true when both guard triggers exist on the table
+func (p Postgres) IsTableReadOnly(schema, table string) (readOnly bool, err error) {
+	query := `SELECT count(*) = 2
+		        FROM pg_catalog.pg_trigger t
+		        JOIN pg_catalog.pg_class c ON c.oid = t.tgrelid
+		        JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		        WHERE n.nspname = $1 AND c.relname = $2 AND t.tgname IN ($3, $4);`
+
+	err = p.conn.QueryRow(p.ctx, query, schema, table, readOnlyTrigger, readOnlyTruncateTrigger).Scan(&readOnly)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if table is read-only: %w", err)
+	}
+
+	return readOnly, nil
+}
@@ -0,0 +1,64 @@
+//nolint:wsl_v5
+package postgresql_test
+
+import (
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTableReadOnly(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+
+	// The function and triggers are created in a single query, so in a single transaction
+	mock.ExpectExec(`(?s)CREATE OR REPLACE FUNCTION "public"."ppm_read_only_guard"\(\).*; ` +
+		`CREATE OR REPLACE TRIGGER "ppm_read_only" BEFORE INSERT OR UPDATE OR DELETE ON "public"."my_table" FOR EACH ROW .*; ` +
+		`CREATE OR REPLACE TRIGGER "ppm_read_only_truncate" BEFORE TRUNCATE ON "public"."my_table" FOR EACH STATEMENT`).WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	err := p.SetTableReadOnly(schema, table)
+	assert.Nil(t, err, "SetTableReadOnly should succeed")
+
+	mock.ExpectExec(`CREATE OR REPLACE FUNCTION`).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.SetTableReadOnly(schema, table)
+	assert.Error(t, err, "SetTableReadOnly should fail")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestUnsetTableReadOnly(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(`DROP TRIGGER IF EXISTS "ppm_read_only" ON "public"."my_table"`).WillReturnResult(pgxmock.NewResult("DROP", 0))
+	mock.ExpectExec(`DROP TRIGGER IF EXISTS "ppm_read_only_truncate" ON "public"."my_table"`).WillReturnResult(pgxmock.NewResult("DROP", 0))
+	err := p.UnsetTableReadOnly(schema, table)
+	assert.Nil(t, err, "UnsetTableReadOnly should succeed")
+
+	mock.ExpectExec(`DROP TRIGGER IF EXISTS "ppm_read_only" ON "public"."my_table"`).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.UnsetTableReadOnly(schema, table)
+	assert.Error(t, err, "UnsetTableReadOnly should fail")
+}
+
+func TestIsTableReadOnly(t *testing.T) {
+	schema, table, _, _ := generateTable(t)
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT count"
+
+	mock.ExpectQuery(query).WithArgs(schema, table, "ppm_read_only", "ppm_read_only_truncate").WillReturnRows(mock.NewRows([]string{"?column?"}).AddRow(true))
+	readOnly, err := p.IsTableReadOnly(schema, table)
+	assert.Nil(t, err, "IsTableReadOnly should succeed")
+	assert.True(t, readOnly, "Table should be read-only")
+
+	// A table with only one of the triggers is not read-only
+	mock.ExpectQuery(query).WithArgs(schema, table, "ppm_read_only", "ppm_read_only_truncate").WillReturnRows(mock.NewRows([]string{"?column?"}).AddRow(false))
+	readOnly, err = p.IsTableReadOnly(schema, table)
+	assert.Nil(t, err, "IsTableReadOnly should succeed")
+	assert.False(t, readOnly, "Table should not be read-only")
+
+	mock.ExpectQuery(query).WithArgs(schema, table, "ppm_read_only", "ppm_read_only_truncate").WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.IsTableReadOnly(schema, table)
+	assert.Error(t, err, "IsTableReadOnly should fail")
+}
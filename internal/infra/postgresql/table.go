@@ -1,11 +1,20 @@
 package postgresql
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 )
 
+// TableResult describes a table and its comment
+type TableResult struct {
+	Schema  string
+	Name    string
+	Comment string
+}
+
 func (p Postgres) CreateTableLikeTable(schema, table, parent string) error {
 	query := fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING ALL)",
 		pgx.Identifier{schema, table}.Sanitize(),
@@ -47,3 +56,311 @@ func (p Postgres) IsTableExists(schema, table string) (exists bool, err error) {
 
 	return exists, nil
 }
+
+// GetTableComment returns the comment of the table, empty when the table has no comment
+func (p Postgres) GetTableComment(schema, table string) (comment string, err error) {
+	query := `SELECT COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), '')
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2`
+
+	err = p.conn.QueryRow(p.ctx, query, schema, table).Scan(&comment)
+	if err != nil {
+		return "", fmt.Errorf("failed to get table comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// SetTableComment replaces the comment of the table
+func (p Postgres) SetTableComment(schema, table, comment string) error {
+	// COMMENT does not support bind parameters
+	query := fmt.Sprintf("COMMENT ON TABLE %s IS '%s'",
+		pgx.Identifier{schema, table}.Sanitize(),
+		strings.ReplaceAll(comment, "'", "''"))
+	p.logger.Debug("Set table comment", "schema", schema, "table", table, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to set table comment: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTableFromDefinition runs a CREATE TABLE statement, such as the one returned by GetColumnsDefinition
+func (p Postgres) CreateTableFromDefinition(definition string) error {
+	p.logger.Debug("Create table from definition", "query", definition)
+
+	_, err := p.conn.Exec(p.ctx, definition)
+	if err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	return nil
+}
+
+func (p Postgres) RenameTable(schema, table, newName string) error {
+	query := fmt.Sprintf("ALTER TABLE %s RENAME TO %s",
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{newName}.Sanitize())
+	p.logger.Debug("Rename table", "schema", schema, "table", table, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to rename table: %w", err)
+	}
+
+	return nil
+}
+
+// RenameTableAndIndexes renames the table and the given indexes in a single transaction,
+// so the indexes never keep the name of the former table
+func (p Postgres) RenameTableAndIndexes(schema, table, newName string, indexes []IndexRename) error {
+	statements := []string{fmt.Sprintf("ALTER TABLE %s RENAME TO %s",
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{newName}.Sanitize())}
+
+	for _, index := range indexes {
+		statements = append(statements, fmt.Sprintf("ALTER INDEX %s RENAME TO %s",
+			pgx.Identifier{schema, index.Name}.Sanitize(),
+			pgx.Identifier{index.NewName}.Sanitize()))
+	}
+
+	query := joinStatements(statements...)
+	p.logger.Debug("Rename table and indexes", "schema", schema, "table", table, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to rename table and indexes: %w", err)
+	}
+
+	return nil
+}
+
+// SetTableSchema moves the table, with its indexes and constraints, to another schema
+func (p Postgres) SetTableSchema(schema, table, newSchema string) error {
+	query := fmt.Sprintf("ALTER TABLE %s SET SCHEMA %s",
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{newSchema}.Sanitize())
+	p.logger.Debug("Move table to schema", "schema", schema, "table", table, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to move table to schema: %w", err)
+	}
+
+	return nil
+}
+
+// ListTablesByComment returns the tables, not attached as a partition, whose comment contains the marker
+func (p Postgres) ListTablesByComment(marker string) (tables []TableResult, err error) {
+	query := `SELECT
+		n.nspname AS schema,
+		c.relname AS name,
+		d.description AS comment
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_catalog.pg_description d ON d.objoid = c.oid AND d.classoid = 'pg_catalog.pg_class'::regclass AND d.objsubid = 0
+	WHERE c.relkind = 'r' AND NOT c.relispartition AND strpos(d.description, $1) > 0
+	ORDER BY n.nspname, c.relname`
+
+	rows, err := p.conn.Query(p.ctx, query, marker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	tables, err = pgx.CollectRows(rows, pgx.RowToStructByName[TableResult])
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast list: %w", err)
+	}
+
+	return tables, nil
+}
+
+// GetTableSize returns the total disk size of the table in bytes, including indexes and TOAST data
+func (p Postgres) GetTableSize(schema, table string) (size int64, err error) {
+	query := `SELECT pg_catalog.pg_total_relation_size(c.oid)
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2`
+
+	err = p.conn.QueryRow(p.ctx, query, schema, table).Scan(&size)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get table size: %w", err)
+	}
+
+	return size, nil
+}
+
+// GetRowEstimate returns the number of rows estimated by the planner statistics, 0 when the table was never analyzed
+func (p Postgres) GetRowEstimate(schema, table string) (rows int64, err error) {
+	query := `SELECT GREATEST(c.reltuples, 0)::bigint
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2`
+
+	err = p.conn.QueryRow(p.ctx, query, schema, table).Scan(&rows)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get row estimate: %w", err)
+	}
+
+	return rows, nil
+}
+
+// HasRowsFrom returns true when the table contains a row whose column is greater than or equal to the value
+func (p Postgres) HasRowsFrom(schema, table, column, value string) (found bool, err error) {
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE %s >= $1)",
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{column}.Sanitize())
+	p.logger.Debug("Look for rows", "schema", schema, "table", table, "query", query, "value", value)
+
+	err = p.conn.QueryRow(p.ctx, query, value).Scan(&found)
+	if err != nil {
+		return false, fmt.Errorf("failed to look for rows: %w", err)
+	}
+
+	return found, nil
+}
+
+// TruncateTable removes all rows of the table
+func (p Postgres) TruncateTable(schema, table string) error {
+	query := fmt.Sprintf("TRUNCATE TABLE %s", pgx.Identifier{schema, table}.Sanitize())
+	p.logger.Debug("Truncate table", "schema", schema, "table", table, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to truncate table: %w", err)
+	}
+
+	return nil
+}
+
+// IsTableEmpty returns true when the table has no row
+func (p Postgres) IsTableEmpty(schema, table string) (empty bool, err error) {
+	query := fmt.Sprintf("SELECT NOT EXISTS (SELECT 1 FROM %s)", pgx.Identifier{schema, table}.Sanitize())
+
+	err = p.conn.QueryRow(p.ctx, query).Scan(&empty)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if table is empty: %w", err)
+	}
+
+	return empty, nil
+}
+
+// DeleteRowsBefore deletes at most limit rows whose column is lower than the value, and returns the number of deleted rows
+func (p Postgres) DeleteRowsBefore(schema, table, column, value string, limit int) (deleted int64, err error) {
+	query := fmt.Sprintf("DELETE FROM %[1]s WHERE ctid IN (SELECT ctid FROM %[1]s WHERE %[2]s < $1 LIMIT $2)",
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{column}.Sanitize())
+	p.logger.Debug("Delete rows", "schema", schema, "table", table, "query", query, "value", value, "limit", limit)
+
+	tag, err := p.conn.Exec(p.ctx, query, value, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete rows: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// GetBatchUpperBound returns the upper bound of a batch of rows with column in [lowerBound, upperBound), ordered by column.
+// The batch contains limit rows, plus the rows sharing its last column value, so a batch always contains at least one value.
+// It returns an empty string when the rows up to upperBound fit in the batch.
+func (p Postgres) GetBatchUpperBound(schema, table, column, lowerBound, upperBound string, limit int) (bound string, err error) {
+	query := fmt.Sprintf(`SELECT %[2]s::text FROM %[1]s WHERE %[2]s > $1 AND %[2]s < $2
+		AND %[2]s >= (SELECT %[2]s FROM %[1]s WHERE %[2]s >= $1 AND %[2]s < $2 ORDER BY %[2]s OFFSET $3 LIMIT 1)
+		ORDER BY %[2]s LIMIT 1`,
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{column}.Sanitize())
+	p.logger.Debug("Get batch upper bound", "schema", schema, "table", table, "query", query, "lower_bound", lowerBound, "upper_bound", upperBound, "limit", limit)
+
+	err = p.conn.QueryRow(p.ctx, query, lowerBound, upperBound, limit).Scan(&bound)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get batch upper bound: %w", err)
+	}
+
+	return bound, nil
+}
+
+// CopyRows copies the rows of the source table with column in [lowerBound, upperBound) to the target table.
+// Columns are named so the copy does not depend on their order, generated columns are left to the target
+// and identity values are kept as they are.
+func (p Postgres) CopyRows(sourceSchema, sourceTable, targetSchema, targetTable, column, lowerBound, upperBound string) (copied int64, err error) {
+	columns, err := p.listInsertableColumns(targetSchema, targetTable)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%[3]s) OVERRIDING SYSTEM VALUE SELECT %[3]s FROM %[2]s WHERE %[4]s >= $1 AND %[4]s < $2",
+		pgx.Identifier{targetSchema, targetTable}.Sanitize(),
+		pgx.Identifier{sourceSchema, sourceTable}.Sanitize(),
+		columns,
+		pgx.Identifier{column}.Sanitize())
+	p.logger.Debug("Copy rows", "schema", sourceSchema, "table", sourceTable, "target", targetTable, "query", query, "lower_bound", lowerBound, "upper_bound", upperBound)
+
+	tag, err := p.conn.Exec(p.ctx, query, lowerBound, upperBound)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy rows: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// AddBoundsConstraint adds a check constraint matching the partition bounds to the table.
+// Attaching the table as a partition then skips the scan validating its rows, which would be made under lock.
+func (p Postgres) AddBoundsConstraint(schema, table, constraint, column, lowerBound, upperBound string) error {
+	query := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%[3]s IS NOT NULL AND %[3]s >= '%[4]s' AND %[3]s < '%[5]s')",
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{constraint}.Sanitize(),
+		pgx.Identifier{column}.Sanitize(),
+		lowerBound, upperBound)
+	p.logger.Debug("Add bounds constraint", "schema", schema, "table", table, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to add bounds constraint: %w", err)
+	}
+
+	return nil
+}
+
+// DropConstraint drops the constraint of the table, if it exists
+func (p Postgres) DropConstraint(schema, table, constraint string) error {
+	query := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s",
+		pgx.Identifier{schema, table}.Sanitize(),
+		pgx.Identifier{constraint}.Sanitize())
+	p.logger.Debug("Drop constraint", "schema", schema, "table", table, "query", query)
+
+	_, err := p.conn.Exec(p.ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to drop constraint: %w", err)
+	}
+
+	return nil
+}
+
+// ListCheckConstraints returns the definitions of the check constraints of the table, ordered by definition
+func (p Postgres) ListCheckConstraints(schema, table string) (definitions []string, err error) {
+	query := `SELECT pg_catalog.pg_get_constraintdef(co.oid)
+	FROM pg_catalog.pg_constraint co
+	JOIN pg_catalog.pg_class c ON c.oid = co.conrelid
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1 AND c.relname = $2 AND co.contype = 'c'
+	ORDER BY 1`
+
+	rows, err := p.conn.Query(p.ctx, query, schema, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check constraints: %w", err)
+	}
+
+	definitions, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast list: %w", err)
+	}
+
+	return definitions, nil
+}
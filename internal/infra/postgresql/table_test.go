@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v3"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -75,3 +76,315 @@ func TestIsTableExists(t *testing.T) {
 	_, err = p.IsTableExists(schema, table)
 	assert.Error(t, err, "IsTableExists should fail")
 }
+
+func TestGetTableComment(t *testing.T) {
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := `SELECT COALESCE\(pg_catalog.obj_description`
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnRows(mock.NewRows([]string{"comment"}).AddRow(`{"budgetedUntil":"2024-06-01T00:00:00Z"}`))
+	comment, err := p.GetTableComment(testSchema, testTable)
+	assert.Nil(t, err, "GetTableComment should succeed")
+	assert.Equal(t, `{"budgetedUntil":"2024-06-01T00:00:00Z"}`, comment)
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.GetTableComment(testSchema, testTable)
+	assert.Error(t, err, "GetTableComment should fail")
+}
+
+func TestSetTableComment(t *testing.T) {
+	schema := testSchema
+	table := testTable
+
+	query := `COMMENT ON TABLE "public"."my_table" IS '{"reason":"it''s held"}'`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("COMMENT", 0))
+	err := p.SetTableComment(schema, table, `{"reason":"it's held"}`)
+	assert.Nil(t, err, "SetTableComment should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.SetTableComment(schema, table, `{"reason":"it's held"}`)
+	assert.Error(t, err, "SetTableComment should fail")
+}
+
+func TestCreateTableFromDefinition(t *testing.T) {
+	query := `CREATE TABLE "public"."my_table" ("id" bigint NOT NULL)`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	err := p.CreateTableFromDefinition(query)
+	assert.Nil(t, err, "CreateTableFromDefinition should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.CreateTableFromDefinition(query)
+	assert.Error(t, err, "CreateTableFromDefinition should fail")
+}
+
+func TestRenameTable(t *testing.T) {
+	query := `ALTER TABLE "public"."my_table" RENAME TO "my_table_detached_20240615"`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.RenameTable(testSchema, testTable, "my_table_detached_20240615")
+	assert.Nil(t, err, "RenameTable should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.RenameTable(testSchema, testTable, "my_table_detached_20240615")
+	assert.Error(t, err, "RenameTable should fail")
+}
+
+func TestRenameTableAndIndexes(t *testing.T) {
+	query := `ALTER TABLE "public"."my_table_20240615" RENAME TO "my_table_2024_06_15"; ` +
+		`ALTER INDEX "public"."my_table_20240615_created_at_idx" RENAME TO "my_table_2024_06_15_created_at_idx"`
+	indexes := []postgresql.IndexRename{{Name: "my_table_20240615_created_at_idx", NewName: "my_table_2024_06_15_created_at_idx"}}
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.RenameTableAndIndexes(testSchema, "my_table_20240615", "my_table_2024_06_15", indexes)
+	assert.Nil(t, err, "RenameTableAndIndexes should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.RenameTableAndIndexes(testSchema, "my_table_20240615", "my_table_2024_06_15", indexes)
+	assert.Error(t, err, "RenameTableAndIndexes should fail")
+}
+
+func TestSetTableSchema(t *testing.T) {
+	query := `ALTER TABLE "public"."my_table" SET SCHEMA "archive"`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.SetTableSchema(testSchema, testTable, "archive")
+	assert.Nil(t, err, "SetTableSchema should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.SetTableSchema(testSchema, testTable, "archive")
+	assert.Error(t, err, "SetTableSchema should fail")
+}
+
+func TestListTablesByComment(t *testing.T) {
+	marker := `"parentTable":"public.my_table"`
+	expected := []postgresql.TableResult{
+		{Schema: "archive", Name: "my_table_2024_01_01", Comment: `{"parentTable":"public.my_table"}`},
+	}
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT"
+
+	rows := mock.NewRows([]string{"schema", "name", "comment"})
+	for _, table := range expected {
+		rows.AddRow(table.Schema, table.Name, table.Comment)
+	}
+	mock.ExpectQuery(query).WithArgs(marker).WillReturnRows(rows)
+	tables, err := p.ListTablesByComment(marker)
+	assert.Nil(t, err, "ListTablesByComment should succeed")
+	assert.Equal(t, expected, tables)
+
+	mock.ExpectQuery(query).WithArgs(marker).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.ListTablesByComment(marker)
+	assert.Error(t, err, "ListTablesByComment should fail")
+}
+
+func TestGetTableSize(t *testing.T) {
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT pg_catalog.pg_total_relation_size"
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnRows(mock.NewRows([]string{"size"}).AddRow(int64(8192)))
+	size, err := p.GetTableSize(testSchema, testTable)
+	assert.Nil(t, err, "GetTableSize should succeed")
+	assert.Equal(t, int64(8192), size)
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.GetTableSize(testSchema, testTable)
+	assert.Error(t, err, "GetTableSize should fail")
+}
+
+func TestGetRowEstimate(t *testing.T) {
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := `SELECT GREATEST\(c.reltuples, 0\)::bigint`
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnRows(mock.NewRows([]string{"rows"}).AddRow(int64(1200)))
+	rows, err := p.GetRowEstimate(testSchema, testTable)
+	assert.Nil(t, err, "GetRowEstimate should succeed")
+	assert.Equal(t, int64(1200), rows)
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.GetRowEstimate(testSchema, testTable)
+	assert.Error(t, err, "GetRowEstimate should fail")
+}
+
+func TestHasRowsFrom(t *testing.T) {
+	query := `SELECT EXISTS (SELECT 1 FROM "public"."my_table" WHERE "created_at" >= $1)`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectQuery(query).WithArgs("2024-06-15").WillReturnRows(mock.NewRows([]string{"exists"}).AddRow(true))
+	found, err := p.HasRowsFrom(testSchema, testTable, "created_at", "2024-06-15")
+	assert.Nil(t, err, "HasRowsFrom should succeed")
+	assert.True(t, found)
+
+	mock.ExpectQuery(query).WithArgs("2024-06-15").WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.HasRowsFrom(testSchema, testTable, "created_at", "2024-06-15")
+	assert.Error(t, err, "HasRowsFrom should fail")
+}
+
+func TestTruncateTable(t *testing.T) {
+	query := `TRUNCATE TABLE "public"."my_table"`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("TRUNCATE", 0))
+	err := p.TruncateTable(testSchema, testTable)
+	assert.Nil(t, err, "TruncateTable should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.TruncateTable(testSchema, testTable)
+	assert.Error(t, err, "TruncateTable should fail")
+}
+
+func TestIsTableEmpty(t *testing.T) {
+	query := `SELECT NOT EXISTS (SELECT 1 FROM "public"."my_table")`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectQuery(query).WillReturnRows(mock.NewRows([]string{"empty"}).AddRow(true))
+	empty, err := p.IsTableEmpty(testSchema, testTable)
+	assert.Nil(t, err, "IsTableEmpty should succeed")
+	assert.True(t, empty)
+
+	mock.ExpectQuery(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.IsTableEmpty(testSchema, testTable)
+	assert.Error(t, err, "IsTableEmpty should fail")
+}
+
+func TestDeleteRowsBefore(t *testing.T) {
+	query := `DELETE FROM "public"."my_table" WHERE ctid IN (SELECT ctid FROM "public"."my_table" WHERE "created_at" < $1 LIMIT $2)`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WithArgs("2024-06-15", 1000).WillReturnResult(pgxmock.NewResult("DELETE", 1000))
+	deleted, err := p.DeleteRowsBefore(testSchema, testTable, "created_at", "2024-06-15", 1000)
+	assert.Nil(t, err, "DeleteRowsBefore should succeed")
+	assert.Equal(t, int64(1000), deleted)
+
+	mock.ExpectExec(query).WithArgs("2024-06-15", 1000).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.DeleteRowsBefore(testSchema, testTable, "created_at", "2024-06-15", 1000)
+	assert.Error(t, err, "DeleteRowsBefore should fail")
+}
+
+func TestGetBatchUpperBound(t *testing.T) {
+	query := `SELECT "created_at"::text FROM "public"."my_table_2025_03" WHERE "created_at" > $1 AND "created_at" < $2
+		AND "created_at" >= (SELECT "created_at" FROM "public"."my_table_2025_03" WHERE "created_at" >= $1 AND "created_at" < $2 ORDER BY "created_at" OFFSET $3 LIMIT 1)
+		ORDER BY "created_at" LIMIT 1`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectQuery(query).WithArgs("2025-03-01", "2025-03-02", 1000).WillReturnRows(mock.NewRows([]string{"created_at"}).AddRow("2025-03-01 12:00:00"))
+	bound, err := p.GetBatchUpperBound(testSchema, "my_table_2025_03", "created_at", "2025-03-01", "2025-03-02", 1000)
+	assert.Nil(t, err, "GetBatchUpperBound should succeed")
+	assert.Equal(t, "2025-03-01 12:00:00", bound)
+
+	mock.ExpectQuery(query).WithArgs("2025-03-01", "2025-03-02", 1000).WillReturnRows(mock.NewRows([]string{"created_at"}))
+	bound, err = p.GetBatchUpperBound(testSchema, "my_table_2025_03", "created_at", "2025-03-01", "2025-03-02", 1000)
+	assert.Nil(t, err, "GetBatchUpperBound should succeed for the last batch")
+	assert.Empty(t, bound)
+
+	mock.ExpectQuery(query).WithArgs("2025-03-01", "2025-03-02", 1000).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.GetBatchUpperBound(testSchema, "my_table_2025_03", "created_at", "2025-03-01", "2025-03-02", 1000)
+	assert.Error(t, err, "GetBatchUpperBound should fail")
+}
+
+func TestCopyRows(t *testing.T) {
+	columnsQuery := "SELECT a.attname FROM pg_catalog.pg_attribute"
+	query := `INSERT INTO "public"."my_table_2025_03_01" \("id", "created_at"\) OVERRIDING SYSTEM VALUE SELECT "id", "created_at" FROM "public"."my_table_2025_03" WHERE "created_at" >= \$1 AND "created_at" < \$2`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+
+	mock.ExpectQuery(columnsQuery).WithArgs(testSchema, "my_table_2025_03_01").WillReturnRows(mock.NewRows([]string{"attname"}).AddRow("id").AddRow("created_at"))
+	mock.ExpectExec(query).WithArgs("2025-03-01", "2025-03-02").WillReturnResult(pgxmock.NewResult("INSERT", 42))
+	copied, err := p.CopyRows(testSchema, "my_table_2025_03", testSchema, "my_table_2025_03_01", "created_at", "2025-03-01", "2025-03-02")
+	assert.Nil(t, err, "CopyRows should succeed")
+	assert.Equal(t, int64(42), copied)
+
+	mock.ExpectQuery(columnsQuery).WithArgs(testSchema, "my_table_2025_03_01").WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.CopyRows(testSchema, "my_table_2025_03", testSchema, "my_table_2025_03_01", "created_at", "2025-03-01", "2025-03-02")
+	assert.Error(t, err, "CopyRows should fail when columns cannot be listed")
+
+	mock.ExpectQuery(columnsQuery).WithArgs(testSchema, "my_table_2025_03_01").WillReturnRows(mock.NewRows([]string{"attname"}).AddRow("id").AddRow("created_at"))
+	mock.ExpectExec(query).WithArgs("2025-03-01", "2025-03-02").WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.CopyRows(testSchema, "my_table_2025_03", testSchema, "my_table_2025_03_01", "created_at", "2025-03-01", "2025-03-02")
+	assert.Error(t, err, "CopyRows should fail")
+}
+
+func TestAddBoundsConstraint(t *testing.T) {
+	query := `ALTER TABLE "public"."my_table_2025_03" ADD CONSTRAINT "ppm_partition_bounds" CHECK ("created_at" IS NOT NULL AND "created_at" >= '2025-03-01' AND "created_at" < '2025-04-01')`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.AddBoundsConstraint(testSchema, "my_table_2025_03", "ppm_partition_bounds", "created_at", "2025-03-01", "2025-04-01")
+	assert.Nil(t, err, "AddBoundsConstraint should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.AddBoundsConstraint(testSchema, "my_table_2025_03", "ppm_partition_bounds", "created_at", "2025-03-01", "2025-04-01")
+	assert.Error(t, err, "AddBoundsConstraint should fail")
+}
+
+func TestDropConstraint(t *testing.T) {
+	query := `ALTER TABLE "public"."my_table_2025_03" DROP CONSTRAINT IF EXISTS "ppm_partition_bounds"`
+
+	mock, p := setupMock(t, pgxmock.QueryMatcherEqual)
+
+	mock.ExpectExec(query).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	err := p.DropConstraint(testSchema, "my_table_2025_03", "ppm_partition_bounds")
+	assert.Nil(t, err, "DropConstraint should succeed")
+
+	mock.ExpectExec(query).WillReturnError(ErrPostgreSQLConnectionFailure)
+	err = p.DropConstraint(testSchema, "my_table_2025_03", "ppm_partition_bounds")
+	assert.Error(t, err, "DropConstraint should fail")
+}
+
+func TestListColumns(t *testing.T) {
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT .* FROM pg_catalog.pg_attribute"
+
+	expected := []postgresql.Column{
+		{Name: "created_at", Type: "date", NotNull: true},
+		{Name: "id", Type: "bigint", NotNull: true},
+		{Name: "status", Type: "character varying(32)", NotNull: false},
+	}
+
+	rows := mock.NewRows([]string{"name", "type", "notnull"})
+	for _, c := range expected {
+		rows.AddRow(c.Name, c.Type, c.NotNull)
+	}
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnRows(rows)
+	columns, err := p.ListColumns(testSchema, testTable)
+	assert.Nil(t, err, "ListColumns should succeed")
+	assert.Equal(t, expected, columns)
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.ListColumns(testSchema, testTable)
+	assert.Error(t, err, "ListColumns should fail")
+}
+
+func TestListCheckConstraints(t *testing.T) {
+	mock, p := setupMock(t, pgxmock.QueryMatcherRegexp)
+	query := "SELECT pg_catalog.pg_get_constraintdef"
+
+	expected := []string{"CHECK ((amount > 0))"}
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnRows(mock.NewRows([]string{"pg_get_constraintdef"}).AddRow(expected[0]))
+	definitions, err := p.ListCheckConstraints(testSchema, testTable)
+	assert.Nil(t, err, "ListCheckConstraints should succeed")
+	assert.Equal(t, expected, definitions)
+
+	mock.ExpectQuery(query).WithArgs(testSchema, testTable).WillReturnError(ErrPostgreSQLConnectionFailure)
+	_, err = p.ListCheckConstraints(testSchema, testTable)
+	assert.Error(t, err, "ListCheckConstraints should fail")
+}
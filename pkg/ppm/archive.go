@@ -0,0 +1,115 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/archive"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var ErrArchiveRowCountMismatch = errors.New("archive row count does not match the partition")
+
+// archiveAttachedPartition archives a partition removed by the drop policy before it is detached.
+// The partition is set read-only first, so no rows are written after the export, and it returns the partitions set read-only.
+// When archiving fails, the partition is writable again.
+func (p PPM) archiveAttachedPartition(config partition.Configuration, part partition.Partition) (frozen []partition.Partition, err error) {
+	if config.CleanupPolicy != partition.Drop || config.Archive == nil {
+		return nil, nil
+	}
+
+	frozen, err = p.setReadOnly([]partition.Partition{part})
+	if err == nil {
+		err = p.ArchivePartition(config, part)
+	}
+
+	if err != nil {
+		p.unsetReadOnly(frozen)
+
+		return nil, err
+	}
+
+	return frozen, nil
+}
+
+// ArchivePartition exports a partition and its manifest to the archive storage, then verifies the archive.
+// The partition must be detached or read-only, so no rows are written during the export.
+func (p PPM) ArchivePartition(config partition.Configuration, part partition.Partition) error {
+	storage, err := archive.NewStorage(config.Archive.Destination)
+	if err != nil {
+		return fmt.Errorf("failed to initialize archive storage: %w", err)
+	}
+
+	columns, err := p.db.GetColumnsDefinition(part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get partition columns: %w", err)
+	}
+
+	manifest := archive.Manifest{
+		Schema:      part.Schema,
+		Table:       part.Name,
+		ParentTable: part.ParentTable,
+		LowerBound:  part.LowerBound,
+		UpperBound:  part.UpperBound,
+		File:        archive.FileName(part.Schema, part.Name, config.Archive.GetFormat(), config.Archive.GetCompression()),
+		Format:      config.Archive.GetFormat(),
+		Compression: config.Archive.GetCompression(),
+		Columns:     columns,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	writer, err := archive.NewWriter(storage, manifest.File, manifest.Compression)
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+
+	manifest.Rows, err = p.db.CopyTableTo(part.Schema, part.Name, string(manifest.Format), writer)
+	if err != nil {
+		writer.Abort() //nolint:errcheck,gosec
+
+		return fmt.Errorf("failed to export partition: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+
+	manifest.Checksum = writer.Checksum()
+
+	err = archive.WriteManifest(storage, manifest)
+	if err != nil {
+		return fmt.Errorf("failed to write archive manifest: %w", err)
+	}
+
+	p.logger.Info("Partition archived", "schema", part.Schema, "table", part.Name, "file", manifest.File, "rows", manifest.Rows)
+
+	return p.verifyArchive(storage, part)
+}
+
+// verifyArchive checks the archive file against its manifest and the manifest row count against the partition
+func (p PPM) verifyArchive(storage archive.Storage, part partition.Partition) error {
+	manifest, err := archive.ReadManifest(storage, part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to read archive manifest: %w", err)
+	}
+
+	err = archive.Verify(storage, manifest)
+	if err != nil {
+		return fmt.Errorf("archive verification failed: %w", err)
+	}
+
+	rows, err := p.db.CountRows(part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to count partition rows: %w", err)
+	}
+
+	if rows != manifest.Rows {
+		return fmt.Errorf("%w: %d rows archived, %d rows in partition", ErrArchiveRowCountMismatch, manifest.Rows, rows)
+	}
+
+	p.logger.Info("Partition archive verified", "schema", part.Schema, "table", part.Name, "file", manifest.File, "checksum", manifest.Checksum)
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package ppm_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/archive"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCleanupArchivesPartitions(t *testing.T) {
+	config := OneDayPartitionConfiguration
+
+	dayBeforeYesterdayPartition, _ := config.GeneratePartition(dayBeforeYesterday)
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+	existing := []partition.Partition{dayBeforeYesterdayPartition, yesterdayPartition, currentPartition, tomorrowPartition}
+	removed := dayBeforeYesterdayPartition
+
+	testCases := []struct {
+		name          string
+		partitionRows int64
+		dropped       bool
+	}{
+		{"Drop partition once archived", 2, true},
+		{"Keep partition attached on row count mismatch", 3, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			directory := t.TempDir()
+
+			config.Archive = &partition.ArchiveConfiguration{
+				Destination: directory,
+				Compression: partition.Gzip,
+			}
+
+			checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+			postgreSQLMock.On("IsTableReadOnly", removed.Schema, removed.Name).Return(false, nil).Once()
+			postgreSQLMock.On("SetTableReadOnly", removed.Schema, removed.Name).Return(nil).Once()
+			postgreSQLMock.On("GetColumnsDefinition", removed.Schema, removed.Name).Return("CREATE TABLE public.my_table (id bigint)", nil).Once()
+			postgreSQLMock.On("CopyTableTo", removed.Schema, removed.Name, "csv", mock.Anything).Run(func(args mock.Arguments) {
+				w, _ := args.Get(3).(io.Writer)
+				_, _ = io.WriteString(w, "id\n1\n2\n")
+			}).Return(int64(2), nil).Once()
+			postgreSQLMock.On("CountRows", removed.Schema, removed.Name).Return(tc.partitionRows, nil).Once()
+
+			// The partition is archived while attached and read-only, it is only detached once the archive is verified
+			postgreSQLMock.On("UnsetTableReadOnly", removed.Schema, removed.Name).Return(nil).Once()
+
+			if tc.dropped {
+				postgreSQLMock.On("DetachPartitionConcurrently", removed.Schema, removed.Name, removed.ParentTable).Return(nil).Once()
+				postgreSQLMock.On("DropTable", removed.Schema, removed.Name).Return(nil).Once()
+			}
+
+			err := checker.CleanupPartitions()
+
+			if tc.dropped {
+				assert.Nil(t, err, "CleanupPartitions should succeed")
+			} else {
+				assert.ErrorIs(t, err, ppm.ErrPartitionCleanupFailed)
+			}
+
+			postgreSQLMock.AssertExpectations(t)
+
+			manifest, err := archive.ReadManifest(archive.NewLocalStorage(directory), removed.Schema, removed.Name)
+			assert.Nil(t, err, "Manifest should be written")
+			assert.Equal(t, int64(2), manifest.Rows)
+			assert.Equal(t, removed.Schema+"."+removed.Name+".csv.gz", manifest.File)
+			assert.Equal(t, removed.LowerBound, manifest.LowerBound)
+		})
+	}
+}
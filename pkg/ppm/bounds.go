@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/uuid7"
 )
 
 const (
@@ -19,6 +20,20 @@ var (
 	ErrUnsupportedUUIDVersion    = errors.New("unsupported UUID version")
 )
 
+// formatBound returns the partition key value matching the date, as expected by the partition bound clauses
+func formatBound(keyType postgresql.ColumnType, bound time.Time) (string, error) {
+	switch keyType {
+	case postgresql.Date:
+		return bound.Format("2006-01-02"), nil
+	case postgresql.DateTime, postgresql.DateTimeWithTZ:
+		return bound.Format("2006-01-02 00:00:00"), nil
+	case postgresql.UUID:
+		return uuid7.FromTime(bound), nil
+	default:
+		return "", ErrUnsupportedPartitionStrategy
+	}
+}
+
 func parseBounds(partition postgresql.PartitionResult) (lowerBound time.Time, upperBound time.Time, err error) {
 	lowerBound, upperBound, err = parseBoundAsDate(partition)
 	if err == nil {
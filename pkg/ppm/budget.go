@@ -0,0 +1,170 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/expression"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+// enforceSizeBudget removes the oldest partitions while the table is larger than the size budget.
+// Current and pre-provisioned partitions, held partitions and the minimum number of partitions are always kept.
+func (p PPM) enforceSizeBudget(config partition.Configuration, keepIf *expression.Expression) error {
+	budget, err := config.SizeBudget.GetMaxSize()
+	if err != nil {
+		return fmt.Errorf("invalid size budget: %w", err)
+	}
+
+	foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	current, err := config.GeneratePartition(p.workDate)
+	if err != nil {
+		return fmt.Errorf("could not generate current partition: %w", err)
+	}
+
+	sizes := make(map[string]int64, len(foundPartitions))
+
+	var total int64
+
+	for _, part := range foundPartitions {
+		size, err := p.db.GetTableSize(part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("could not get partition size: %w", err)
+		}
+
+		sizes[part.Name] = size
+		total += size
+	}
+
+	if total <= budget {
+		p.logger.Debug("Table is within its size budget", "schema", config.Schema, "table", config.Table, "size", total, "budget", budget)
+
+		return nil
+	}
+
+	slices.SortFunc(foundPartitions, func(a, b partition.Partition) int {
+		return a.LowerBound.Compare(b.LowerBound)
+	})
+
+	var removable []partition.Partition
+
+	remaining := total
+	count := len(foundPartitions)
+
+	for _, part := range foundPartitions {
+		if remaining <= budget || count <= config.SizeBudget.MinPartitions || part.UpperBound.After(current.LowerBound) {
+			break
+		}
+
+		if kept, reason := p.isKept(config, keepIf, part); kept {
+			p.logger.Info("Partition is kept, skip", "schema", part.Schema, "table", part.Name, "reason", reason, "hold_until", part.Metadata.HoldUntil)
+
+			continue
+		}
+
+		removable = append(removable, part)
+		remaining -= sizes[part.Name]
+		count--
+	}
+
+	if remaining > budget {
+		p.logger.Warn("Size budget can't be met without removing kept partitions", "schema", config.Schema, "table", config.Table, "size", remaining, "budget", budget)
+	}
+
+	removable, err = p.applyRetentionGate(config, removable)
+	if err != nil {
+		return err
+	}
+
+	removable, err = p.applySafeguards(config, foundPartitions, removable, true)
+	if err != nil && !errors.Is(err, ErrCleanupRefused) {
+		return err
+	}
+
+	removalFailed := errors.Is(err, ErrCleanupRefused)
+
+	var removedUntil time.Time
+
+	for _, part := range removable {
+		p.logger.Info("Partition exceeds the size budget", "schema", part.Schema, "table", part.Name, "size", sizes[part.Name], "budget", budget)
+
+		if err := p.removePartition(config, part); err != nil {
+			removalFailed = true
+
+			break
+		}
+
+		removedUntil = part.UpperBound
+	}
+
+	if !removedUntil.IsZero() {
+		err = p.recordBudgetedPartitions(config, removedUntil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if removalFailed {
+		return ErrPartitionCleanupFailed
+	}
+
+	return nil
+}
+
+// recordBudgetedPartitions records in the parent table comment that partitions before a date were removed by the size budget
+func (p PPM) recordBudgetedPartitions(config partition.Configuration, until time.Time) error {
+	comment, err := p.db.GetTableComment(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not get parent table comment: %w", err)
+	}
+
+	metadata := partition.ParseMetadata(comment)
+	if !until.After(metadata.BudgetedUntil) {
+		return nil
+	}
+
+	metadata.BudgetedUntil = until
+
+	comment, err = metadata.MergeComment(comment)
+	if err != nil {
+		return err
+	}
+
+	err = p.db.SetTableComment(config.Schema, config.Table, comment)
+	if err != nil {
+		return fmt.Errorf("could not record partitions removed by the size budget: %w", err)
+	}
+
+	return nil
+}
+
+// withoutBudgetedPartitions removes the expected partitions removed by the size budget, as recorded in the parent table comment.
+// The size budget removes partitions before the end of their retention, they must neither be created again nor be reported as missing.
+func (p PPM) withoutBudgetedPartitions(config partition.Configuration, expected []partition.Partition) ([]partition.Partition, error) {
+	if config.SizeBudget == nil {
+		return expected, nil
+	}
+
+	comment, err := p.db.GetTableComment(config.Schema, config.Table)
+	if err != nil {
+		return nil, fmt.Errorf("could not get parent table comment: %w", err)
+	}
+
+	budgetedUntil := partition.ParseMetadata(comment).BudgetedUntil
+
+	var result []partition.Partition
+
+	for _, part := range expected {
+		if part.UpperBound.After(budgetedUntil) {
+			result = append(result, part)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package ppm_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupEnforcesSizeBudget(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		minPartitions int
+		safeguards    *partition.SafeguardConfiguration
+		removed       int
+	}{
+		{"Remove oldest partitions until the table fits", 0, nil, 2},
+		{"Keep the minimum number of partitions", 4, nil, 1},
+		// Partitions removed by the size budget are within the retention, their rows are not compared to the retention cutoff
+		{"Skip the recent rows safeguard", 0, &partition.SafeguardConfiguration{CheckRecentRows: true}, 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := OneDayPartitionConfiguration
+			config.Retention = 3
+			config.SizeBudget = &partition.SizeBudgetConfiguration{MaxSize: "3kB", MinPartitions: tc.minPartitions}
+			config.Safeguards = tc.safeguards
+
+			var existing []partition.Partition
+
+			for i := -3; i <= 1; i++ {
+				part, _ := config.GeneratePartition(workDate.AddDate(0, 0, i))
+				existing = append(existing, part)
+			}
+
+			checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Twice()
+
+			for _, part := range existing {
+				postgreSQLMock.On("GetTableSize", part.Schema, part.Name).Return(int64(1024), nil).Once()
+			}
+
+			for _, part := range existing[:tc.removed] {
+				postgreSQLMock.On("DetachPartitionConcurrently", part.Schema, part.Name, part.ParentTable).Return(nil).Once()
+				postgreSQLMock.On("DropTable", part.Schema, part.Name).Return(nil).Once()
+			}
+
+			// Removals are recorded on the parent table, next to the user fields of its comment
+			postgreSQLMock.On("GetTableComment", config.Schema, config.Table).Return(`{"owner":"data"}`, nil).Once()
+			postgreSQLMock.On("SetTableComment", config.Schema, config.Table,
+				fmt.Sprintf(`{"budgetedUntil":"%s","owner":"data"}`, existing[tc.removed-1].UpperBound.Format(time.RFC3339))).Return(nil).Once()
+
+			assert.Nil(t, checker.CleanupPartitions(), "CleanupPartitions should succeed")
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSizeBudgetNeverRemovesCurrentPartitions(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.Retention = 3
+	config.SizeBudget = &partition.SizeBudgetConfiguration{MaxSize: "3kB"}
+
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil)
+	postgreSQLMock.On("GetTableComment", config.Schema, config.Table).Return(`{"budgetedUntil":"2024-06-15T00:00:00Z"}`, nil).Once()
+	postgreSQLMock.On("GetTableSize", currentPartition.Schema, currentPartition.Name).Return(int64(4096), nil).Once()
+	postgreSQLMock.On("GetTableSize", tomorrowPartition.Schema, tomorrowPartition.Name).Return(int64(0), nil).Once()
+
+	assert.Nil(t, checker.ProvisioningPartitions(), "ProvisioningPartitions should succeed")
+	assert.Nil(t, checker.CleanupPartitions(), "CleanupPartitions should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestCheckIgnoresBudgetedPartitions(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	config.Retention = 3
+	config.SizeBudget = &partition.SizeBudgetConfiguration{MaxSize: "3kB"}
+
+	var existing []partition.Partition
+
+	for i := -1; i <= 1; i++ {
+		part, _ := config.GeneratePartition(workDate.AddDate(0, 0, i))
+		existing = append(existing, part)
+	}
+
+	testCases := []struct {
+		name    string
+		comment string
+		success bool
+	}{
+		{"Partitions removed by the size budget", `{"budgetedUntil":"2024-06-14T00:00:00Z"}`, true},
+		// Only recorded removals are ignored, other missing partitions are reported
+		{"Partitions removed by the size budget are not recorded", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+			postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+			postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+			postgreSQLMock.On("GetTableComment", config.Schema, config.Table).Return(tc.comment, nil).Once()
+
+			err := checker.CheckPartitions()
+
+			if tc.success {
+				assert.Nil(t, err, "CheckPartitions should succeed")
+			} else {
+				assert.ErrorIs(t, err, ppm.ErrInvalidPartitionConfiguration)
+			}
+
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
@@ -16,6 +16,7 @@ var (
 	ErrUnsupportedPartitionStrategy  = errors.New("unsupported partitioning strategy on the table")
 	ErrPartitionKeyMismatch          = errors.New("mismatch of partition keys between parameters and table")
 	ErrUnexpectedOrMissingPartitions = errors.New("unexpected or missing partitions")
+	ErrPartitionNameMismatch         = errors.New("partitions do not follow the naming convention")
 	ErrInvalidPartitionConfiguration = errors.New("at least one partition contains an invalid configuration")
 	ErrPartitionGap                  = errors.New("gap found in partitions")
 	ErrIncoherentBounds              = errors.New("lower bound greater or equal than upper bound")
@@ -64,6 +65,27 @@ func (p *PPM) checkPartition(config partition.Configuration) error {
 		return fmt.Errorf("failed to check partitions configuration: %w", err)
 	}
 
+	if config.CheckIndexes {
+		err = p.checkPartitionIndexes(config)
+		if err != nil {
+			return fmt.Errorf("failed to check partition indexes: %w", err)
+		}
+	}
+
+	if len(config.Publications) > 0 {
+		err = p.checkPublications(config)
+		if err != nil {
+			return fmt.Errorf("failed to check publications: %w", err)
+		}
+	}
+
+	if config.ReadOnlyAfter > 0 {
+		err = p.checkReadOnlyPartitions(config)
+		if err != nil {
+			return fmt.Errorf("failed to check read-only partitions: %w", err)
+		}
+	}
+
 	p.logger.Debug("Partitions match the configuration", "schema", config.Schema, "table", config.Table)
 
 	return nil
@@ -111,47 +133,66 @@ func IsSupportedKeyDataType(dataType postgresql.ColumnType) bool {
 	return slices.Contains(SupportedPartitionKeyDataType, dataType)
 }
 
-func (p *PPM) comparePartitions(existingTables, expectedTables []partition.Partition) (unexpectedTables, missingTables, incorrectBounds []partition.Partition) {
-	existing := make(map[string]partition.Partition)
-	expectedAndExists := make(map[string]bool)
+// comparePartitions matches existing and expected partitions on their bounds first, then on their names.
+// Partitions matching on bounds with a different name are reported as foreign names, not as unexpected and missing.
+func (p *PPM) comparePartitions(existingTables, expectedTables []partition.Partition) (unexpectedTables, missingTables, incorrectBounds, foreignNames []partition.Partition) {
+	byBounds := make(map[partition.PartitionRange]partition.Partition)
+	byName := make(map[string]partition.Partition)
+	matched := make(map[string]bool)
 
 	for _, t := range existingTables {
-		existing[t.Name] = t
+		byBounds[partition.Bounds(t.LowerBound, t.UpperBound)] = t
+		byName[t.Name] = t
 	}
 
 	for _, t := range expectedTables {
-		if _, found := existing[t.Name]; found {
-			expectedAndExists[t.Name] = true
-			incorrectBound := false
+		if existing, found := byBounds[partition.Bounds(t.LowerBound, t.UpperBound)]; found {
+			matched[existing.Name] = true
 
-			if existing[t.Name].UpperBound != t.UpperBound {
-				incorrectBound = true
+			if existing.Name != t.Name {
+				p.logger.Debug("Partition name does not follow the naming convention", "schema", existing.Schema, "table", existing.Name, "expected_name", t.Name)
 
-				p.logger.Warn("Incorrect upper partition bound", "schema", t.Schema, "table", t.Name, "current_bound", existing[t.Name].UpperBound, "expected_bound", t.UpperBound)
+				foreignNames = append(foreignNames, existing)
 			}
 
-			if existing[t.Name].LowerBound != t.LowerBound {
-				incorrectBound = true
-
-				p.logger.Warn("Incorrect lower partition bound", "schema", t.Schema, "table", t.Name, "current_bound", existing[t.Name].LowerBound, "expected_bound", t.LowerBound)
-			}
+			continue
+		}
 
-			if incorrectBound {
-				incorrectBounds = append(incorrectBounds, t)
-			}
-		} else {
+		existing, found := byName[t.Name]
+		if !found || matched[existing.Name] {
 			missingTables = append(missingTables, t)
+
+			continue
+		}
+
+		matched[existing.Name] = true
+		incorrectBound := false
+
+		if existing.UpperBound != t.UpperBound {
+			incorrectBound = true
+
+			p.logger.Warn("Incorrect upper partition bound", "schema", t.Schema, "table", t.Name, "current_bound", existing.UpperBound, "expected_bound", t.UpperBound)
+		}
+
+		if existing.LowerBound != t.LowerBound {
+			incorrectBound = true
+
+			p.logger.Warn("Incorrect lower partition bound", "schema", t.Schema, "table", t.Name, "current_bound", existing.LowerBound, "expected_bound", t.LowerBound)
+		}
+
+		if incorrectBound {
+			incorrectBounds = append(incorrectBounds, t)
 		}
 	}
 
 	for _, t := range existingTables {
-		if _, found := expectedAndExists[t.Name]; !found {
+		if !matched[t.Name] {
 			// Only in existingTables and not in both
 			unexpectedTables = append(unexpectedTables, t)
 		}
 	}
 
-	return unexpectedTables, missingTables, incorrectBounds
+	return unexpectedTables, missingTables, incorrectBounds, foreignNames
 }
 
 func (p *PPM) ListPartitions(schema, table string) (partitions []partition.Partition, err error) {
@@ -160,6 +201,10 @@ func (p *PPM) ListPartitions(schema, table string) (partitions []partition.Parti
 		return nil, fmt.Errorf("could not list partitions: %w", err)
 	}
 
+	return toPartitions(rawPartitions)
+}
+
+func toPartitions(rawPartitions []postgresql.PartitionResult) (partitions []partition.Partition, err error) {
 	for _, p := range rawPartitions {
 		lowerBound, upperBound, err := parseBounds(p)
 		if err != nil {
@@ -172,6 +217,8 @@ func (p *PPM) ListPartitions(schema, table string) (partitions []partition.Parti
 			ParentTable: p.ParentTable,
 			LowerBound:  lowerBound,
 			UpperBound:  upperBound,
+			Metadata:    partition.ParseMetadata(p.Comment),
+			Comment:     p.Comment,
 		})
 	}
 
@@ -191,21 +238,44 @@ func (p *PPM) checkPartitionsConfiguration(config partition.Configuration) error
 		return fmt.Errorf("could not list partitions: %w", err)
 	}
 
-	existingRange, err := p.getGlobalRange(foundPartitions)
+	expectedPartitions, err = p.withoutBudgetedPartitions(config, expectedPartitions)
 	if err != nil {
-		return fmt.Errorf("incorrect set of existing partitions: %w", err)
+		return fmt.Errorf("could not evaluate partitions removed by the size budget: %w", err)
 	}
 
-	p.logger.Info("Existing range", "range", existingRange)
-
 	expectedRange, err := p.getGlobalRange(expectedPartitions)
 	if err != nil {
 		return fmt.Errorf("incorrect set of expected partitions: %w", err)
 	}
 
+	foundPartitions = p.withoutKeptPartitions(config, nil, foundPartitions, expectedRange)
+
+	if config.CleanupPolicy == partition.Truncate {
+		// Expired partitions are kept empty on purpose
+		_, foundPartitions = splitExpiredPartitions(foundPartitions, expectedRange)
+	}
+
+	// Sparse partition sets are compared partition by partition, gaps between existing partitions are expected
+	if !config.Sparse {
+		existingRange, err := p.getGlobalRange(foundPartitions)
+		if err != nil {
+			return fmt.Errorf("incorrect set of existing partitions: %w", err)
+		}
+
+		p.logger.Info("Existing range", "range", existingRange)
+	}
+
 	p.logger.Info("Expected range", "expected", expectedRange)
 
-	unexpected, missing, incorrectBound := p.comparePartitions(foundPartitions, expectedPartitions)
+	unexpected, missing, incorrectBound, foreignNames := p.comparePartitions(foundPartitions, expectedPartitions)
+
+	if config.Sparse {
+		// Gaps between existing partitions are expected, only partitions after the newest one must exist
+		newest := newestUpperBound(foundPartitions)
+		missing = slices.DeleteFunc(missing, func(part partition.Partition) bool {
+			return part.LowerBound.Before(newest)
+		})
+	}
 
 	if len(unexpected) > 0 {
 		partitionContainAnError = true
@@ -229,6 +299,16 @@ func (p *PPM) checkPartitionsConfiguration(config partition.Configuration) error
 		return ErrUnexpectedOrMissingPartitions
 	}
 
+	if len(foreignNames) > 0 {
+		if config.StrictNames {
+			p.logger.Error("Found partitions with foreign names, rename them", "tables", foreignNames)
+
+			return ErrPartitionNameMismatch
+		}
+
+		p.logger.Warn("Found partitions with foreign names, run rename to follow the naming convention", "tables", foreignNames)
+	}
+
 	return nil
 }
 
@@ -205,3 +205,28 @@ func TestGetExpectedPartitionsContiguity(t *testing.T) {
 		})
 	}
 }
+
+func TestComparePartitionsMatchesBoundsFirst(t *testing.T) {
+	p := newTestPPM()
+	config := partition.Configuration{Schema: "public", Table: "my_table", Interval: partition.Daily}
+
+	first, _ := config.GeneratePartition(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	second, _ := config.GeneratePartition(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	third, _ := config.GeneratePartition(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC))
+
+	foreign := second
+	foreign.Name = "legacy_20260102"
+
+	wrongBounds := third
+	wrongBounds.UpperBound = third.UpperBound.AddDate(0, 0, 1)
+
+	unexpected, missing, incorrectBounds, foreignNames := p.comparePartitions(
+		[]partition.Partition{first, foreign, wrongBounds},
+		[]partition.Partition{first, second, third},
+	)
+
+	assert.Equal(t, len(unexpected), 0)
+	assert.Equal(t, len(missing), 0)
+	assert.DeepEqual(t, incorrectBounds, []partition.Partition{third})
+	assert.DeepEqual(t, foreignNames, []partition.Partition{foreign})
+}
@@ -36,6 +36,15 @@ func setupMocks(t *testing.T) (*slog.Logger, *mocks.PostgreSQLClient) {
 	return logger, &postgreSQLMock
 }
 
+// setupPPM returns a PPM managing the configuration as "unittest", and the mock of its database
+func setupPPM(t *testing.T, config partition.Configuration, workDate time.Time) (*ppm.PPM, *mocks.PostgreSQLClient) {
+	t.Helper()
+
+	logger, postgreSQLMock := setupMocks(t)
+
+	return ppm.New(context.TODO(), *logger, postgreSQLMock, map[string]partition.Configuration{"unittest": config}, workDate), postgreSQLMock
+}
+
 func TestCheckPartitions(t *testing.T) {
 	logger, postgreSQLMock := setupMocks(t)
 
@@ -206,3 +215,45 @@ func TestUnsupportedPartitionsStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckPartitionsWithForeignNames(t *testing.T) {
+	yesterdayPartition, _ := OneDayPartitionConfiguration.GeneratePartition(yesterday)
+	currentPartition, _ := OneDayPartitionConfiguration.GeneratePartition(today)
+	tomorrowPartition, _ := OneDayPartitionConfiguration.GeneratePartition(tomorrow)
+
+	// Created manually with the expected bounds but another name
+	currentPartition.Name = "my_table_legacy"
+	existing := []partition.Partition{yesterdayPartition, currentPartition, tomorrowPartition}
+
+	testCases := []struct {
+		name   string
+		strict bool
+	}{
+		{"Foreign names accepted by default", false},
+		{"Foreign names rejected with strict names", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := OneDayPartitionConfiguration
+			config.StrictNames = tc.strict
+
+			logger, postgreSQLMock := setupMocks(t)
+
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+			postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+			postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+
+			checker := ppm.New(context.TODO(), *logger, postgreSQLMock, map[string]partition.Configuration{"unittest": config}, time.Now())
+			err := checker.CheckPartitions()
+
+			if tc.strict {
+				assert.ErrorContains(t, err, ppm.ErrInvalidPartitionConfiguration.Error())
+			} else {
+				assert.NilError(t, err, "Check should only warn about foreign names")
+			}
+
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
@@ -3,7 +3,9 @@ package ppm
 import (
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/qonto/postgresql-partition-manager/internal/infra/expression"
 	partition_pkg "github.com/qonto/postgresql-partition-manager/internal/infra/partition"
 	"github.com/qonto/postgresql-partition-manager/internal/infra/retry"
 )
@@ -13,21 +15,37 @@ var ErrPartitionCleanupFailed = errors.New("at least one partition could not be
 func (p PPM) CleanupPartitions() error {
 	partitionContainAnError := false
 
+	// keepIf expressions are parsed once per run, and evaluated for each partition
+	keepIfs := make(map[string]*expression.Expression, len(p.partitions))
+
 	for name, config := range p.partitions {
 		p.logger.Info("Cleaning partition", "partition", name)
 
-		// Existing
-		foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+		keepIf, err := config.ParseKeepIf()
 		if err != nil {
-			return fmt.Errorf("could not list partitions: %w", err)
+			partitionContainAnError = true
+
+			p.logger.Error("Failed to parse keepIf expression", "schema", config.Schema, "table", config.Table, "error", err)
+
+			continue
 		}
 
-		currentRange, err := p.getGlobalRange(foundPartitions)
-		if err != nil {
-			return fmt.Errorf("could not evaluate existing ranges: %w", err)
+		keepIfs[name] = keepIf
+
+		if config.GracePeriod > 0 {
+			err := p.dropExpiredPartitions(config)
+			if err != nil {
+				partitionContainAnError = true
+
+				p.logger.Error("Failed to drop partitions past the grace period", "schema", config.Schema, "table", config.Table, "error", err)
+			}
 		}
 
-		p.logger.Info("Current ", "c_range", currentRange.String())
+		// Existing
+		foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+		if err != nil {
+			return fmt.Errorf("could not list partitions: %w", err)
+		}
 
 		// Expected
 		expectedPartitions, err := getExpectedPartitions(config, p.workDate)
@@ -40,41 +58,100 @@ func (p PPM) CleanupPartitions() error {
 			return fmt.Errorf("could not evaluate ranges to create: %w", err)
 		}
 
-		p.logger.Info("Expected", "e_range", expectedRange)
+		foundPartitions = p.withoutKeptPartitions(config, keepIf, foundPartitions, expectedRange)
+
+		// Sparse partition sets, retention tiers and keepIf leave gaps in the history, so expired partitions are removed without checking the existing range
+		if !config.AllowsGaps() {
+			currentRange, err := p.getGlobalRange(foundPartitions)
+			if err != nil {
+				return fmt.Errorf("could not evaluate existing ranges: %w", err)
+			}
+
+			p.logger.Info("Current ", "c_range", currentRange.String())
+			p.logger.Info("Expected", "e_range", expectedRange)
 
-		if expectedRange.IsEqual(currentRange) {
-			continue // nothing to do on this partition set
+			if expectedRange.IsEqual(currentRange) {
+				continue // nothing to do on this partition set
+			}
 		}
 
 		// Each partition whose bounds are entirely outside of expectedRange can be removed
+		var removable []partition_pkg.Partition
 
 		for _, part := range foundPartitions {
 			if !part.UpperBound.After(expectedRange.LowerBound) || !part.LowerBound.Before(expectedRange.UpperBound) {
-				p.logger.Info("No intersection", "remove-range", partition_pkg.Bounds(part.LowerBound, part.UpperBound))
+				removable = append(removable, part)
+			}
+		}
+
+		removable, err = p.applyRetentionGate(config, removable)
+		if err != nil {
+			partitionContainAnError = true
+
+			p.logger.Error("Failed to evaluate retention gate", "schema", config.Schema, "table", config.Table, "error", err)
 
-				err := p.DetachPartition(part)
+			continue
+		}
+
+		removable, err = p.applySafeguards(config, foundPartitions, removable, false)
+		if err != nil {
+			partitionContainAnError = true
+
+			if !errors.Is(err, ErrCleanupRefused) {
+				p.logger.Error("Failed to evaluate cleanup safeguards", "schema", config.Schema, "table", config.Table, "error", err)
+			}
+		}
+
+		// Removable partitions are recycled from the oldest one, once they passed the retention gate and safeguards
+		targets := recycleTargets(config, expectedPartitions, foundPartitions)
+		slices.SortFunc(removable, func(a, b partition_pkg.Partition) int {
+			return a.LowerBound.Compare(b.LowerBound)
+		})
+
+		for _, part := range removable {
+			p.logger.Info("No intersection", "remove-range", partition_pkg.Bounds(part.LowerBound, part.UpperBound))
+
+			if len(targets) > 0 {
+				err := p.recyclePartition(config, part, targets[0])
 				if err != nil {
 					partitionContainAnError = true
 
-					p.logger.Error("Failed to detach partition", "schema", part.Schema, "table", part.Name, "error", err)
-
-					continue
+					p.logger.Error("Failed to recycle partition", "schema", part.Schema, "table", part.Name, "target", targets[0].Name, "error", err)
 				}
 
-				p.logger.Info("Partition detached", "schema", part.Schema, "table", part.Name, "parent_table", part.ParentTable)
+				targets = targets[1:]
+
+				continue
+			}
+
+			if err := p.removePartition(config, part); err != nil {
+				partitionContainAnError = true
+			}
+		}
+	}
+
+	// Size budget and precise retention run once partitions are cleaned, so they only apply to retained partitions
+	for name, config := range p.partitions {
+		keepIf, parsed := keepIfs[name]
+		if !parsed {
+			continue
+		}
 
-				if config.CleanupPolicy == partition_pkg.Drop {
-					err := p.DeletePartition(part)
-					if err != nil {
-						partitionContainAnError = true
+		if config.SizeBudget != nil {
+			err := p.enforceSizeBudget(config, keepIf)
+			if err != nil {
+				partitionContainAnError = true
 
-						p.logger.Error("Failed to delete partition", "schema", part.Schema, "table", part.Name, "error", err)
+				p.logger.Error("Failed to enforce size budget", "schema", config.Schema, "table", config.Table, "error", err)
+			}
+		}
 
-						continue
-					}
+		if config.PreciseRetention != nil {
+			err := p.enforcePreciseRetention(config, keepIf)
+			if err != nil {
+				partitionContainAnError = true
 
-					p.logger.Info("Partition deleted", "schema", part.Schema, "table", part.Name, "parent_table", part.ParentTable)
-				}
+				p.logger.Error("Failed to enforce precise retention", "schema", config.Schema, "table", config.Table, "error", err)
 			}
 		}
 	}
@@ -88,6 +165,91 @@ func (p PPM) CleanupPartitions() error {
 	return nil
 }
 
+// removePartition applies the cleanup policy to a partition outside of the retention.
+// Failures are logged, and the partition is left in the state reached so far.
+func (p PPM) removePartition(config partition_pkg.Configuration, part partition_pkg.Partition) error {
+	if config.CleanupPolicy == partition_pkg.Truncate {
+		err := p.truncatePartition(config, part)
+		if err != nil {
+			p.logger.Error("Failed to truncate partition", "schema", part.Schema, "table", part.Name, "error", err)
+		}
+
+		return err
+	}
+
+	if recordsDetachMetadata(config) {
+		// Checked before detaching, so a partition whose comment can't store the metadata stays attached
+		_, err := part.Metadata.MergeComment(part.Comment)
+		if err != nil {
+			p.logger.Error("Partition comment can't store the detach metadata, keep the partition attached", "schema", part.Schema, "table", part.Name, "error", err)
+
+			return err
+		}
+	}
+
+	// Partitions are archived while attached, so a failed archive leaves them in place for the next cleanup
+	frozen, err := p.archiveAttachedPartition(config, part)
+	if err != nil {
+		p.logger.Error("Failed to archive partition, keep it attached", "schema", part.Schema, "table", part.Name, "error", err)
+
+		return err
+	}
+
+	err = p.DetachPartition(part)
+
+	// Once detached, rows can't be written to the partition through the parent table anymore
+	p.unsetReadOnly(frozen)
+
+	if err != nil {
+		p.logger.Error("Failed to detach partition", "schema", part.Schema, "table", part.Name, "error", err)
+
+		return err
+	}
+
+	p.logger.Info("Partition detached", "schema", part.Schema, "table", part.Name, "parent_table", part.ParentTable)
+
+	err = p.removeFromPublications(config, part)
+	if err != nil {
+		p.logger.Error("Failed to remove partition from publications", "schema", part.Schema, "table", part.Name, "error", err)
+
+		return err
+	}
+
+	if config.CleanupPolicy != partition_pkg.Drop {
+		if config.DetachedSchema == "" && !config.RenameDetached {
+			return nil
+		}
+
+		err = p.moveDetachedPartition(config, part)
+		if err != nil {
+			p.logger.Error("Failed to move detached partition", "schema", part.Schema, "table", part.Name, "error", err)
+		}
+
+		return err
+	}
+
+	if config.GracePeriod > 0 {
+		// The partition is dropped by a later cleanup, once the grace period is over
+		err = p.moveDetachedPartition(config, part)
+		if err != nil {
+			p.logger.Error("Failed to record detached partition", "schema", part.Schema, "table", part.Name, "error", err)
+		}
+
+		return err
+	}
+
+	err = p.DeletePartition(part)
+	if err != nil {
+		p.logger.Error("Failed to delete partition", "schema", part.Schema, "table", part.Name, "error", err)
+
+		return err
+	}
+
+	p.logger.Info("Partition deleted", "schema", part.Schema, "table", part.Name, "parent_table", part.ParentTable)
+
+	return nil
+}
+
 func (p PPM) DetachPartition(partition partition_pkg.Partition) error {
 	p.logger.Debug("Detach partition", "schema", partition.Schema, "table", partition.Name)
 
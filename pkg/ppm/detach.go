@@ -0,0 +1,78 @@
+package ppm
+
+import (
+	"fmt"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var ErrIdentifierTooLong = partition.ErrIdentifierTooLong
+
+// checkIdentifier returns an error for names PostgreSQL would truncate, as the truncated name may belong to another table
+func checkIdentifier(name string) error {
+	if len(name) > partition.MaxIdentifierLength {
+		return fmt.Errorf("%w: %s", ErrIdentifierTooLong, name)
+	}
+
+	return nil
+}
+
+// recordsDetachMetadata returns true when cleanup records metadata on the partitions it detaches
+func recordsDetachMetadata(config partition.Configuration) bool {
+	if config.CleanupPolicy == partition.Drop {
+		return config.GracePeriod > 0
+	}
+
+	return config.CleanupPolicy == partition.Detach && (config.DetachedSchema != "" || config.RenameDetached)
+}
+
+// moveDetachedPartition records the origin and bounds of a detached partition in its metadata,
+// then renames it with a detached suffix and/or moves it to the detached schema
+func (p PPM) moveDetachedPartition(config partition.Configuration, part partition.Partition) error {
+	name := part.Name
+	if config.RenameDetached {
+		name = config.DetachedName(part.Name, p.workDate)
+
+		err := checkIdentifier(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	schema := part.Schema
+	if config.DetachedSchema != "" {
+		schema = config.DetachedSchema
+	}
+
+	part.Metadata.DetachedAt = p.workDate
+	part.Metadata.ParentTable = fmt.Sprintf("%s.%s", config.Schema, config.Table)
+	part.Metadata.OriginalName = part.QualifiedName()
+	part.Metadata.LowerBound = part.LowerBound
+	part.Metadata.UpperBound = part.UpperBound
+
+	// Metadata is recorded first, since the comment follows the table when it is renamed or moved
+	err := p.setMetadata(part)
+	if err != nil {
+		return err
+	}
+
+	if name != part.Name {
+		err = p.db.RenameTable(part.Schema, part.Name, name)
+		if err != nil {
+			return fmt.Errorf("failed to rename detached partition: %w", err)
+		}
+	}
+
+	if schema != part.Schema {
+		err = p.db.SetTableSchema(part.Schema, name, schema)
+		if err != nil {
+			return fmt.Errorf("failed to move detached partition: %w", err)
+		}
+	}
+
+	if name != part.Name || schema != part.Schema {
+		p.logger.Info("Detached partition moved", "schema", part.Schema, "table", part.Name, "new_schema", schema, "new_name", name)
+	}
+
+	return nil
+}
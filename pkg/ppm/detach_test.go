@@ -0,0 +1,141 @@
+package ppm_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupMovesDetachedPartitions(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	config.CleanupPolicy = partition.Detach
+
+	removed, _ := config.GeneratePartition(workDate.AddDate(0, 0, -2))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{removed, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	metadata := `{"detachedAt":"2024-06-15T00:00:00Z","parentTable":"public.my_table","originalName":"public.my_table_2024_06_13",` +
+		`"lowerBound":"2024-06-13T00:00:00Z","upperBound":"2024-06-14T00:00:00Z"}`
+	detachedName := "my_table_2024_06_13_detached_20240615"
+
+	testCases := []struct {
+		name           string
+		detachedSchema string
+		renameDetached bool
+	}{
+		{"Move to detached schema", "archive", false},
+		{"Rename detached partition", "", true},
+		{"Rename and move detached partition", "archive", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config.DetachedSchema = tc.detachedSchema
+			config.RenameDetached = tc.renameDetached
+
+			checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+			postgreSQLMock.On("DetachPartitionConcurrently", removed.Schema, removed.Name, removed.ParentTable).Return(nil).Once()
+			postgreSQLMock.On("SetTableComment", removed.Schema, removed.Name, metadata).Return(nil).Once()
+
+			name := removed.Name
+			if tc.renameDetached {
+				name = detachedName
+				postgreSQLMock.On("RenameTable", removed.Schema, removed.Name, detachedName).Return(nil).Once()
+			}
+
+			if tc.detachedSchema != "" {
+				postgreSQLMock.On("SetTableSchema", removed.Schema, name, tc.detachedSchema).Return(nil).Once()
+			}
+
+			err := checker.CleanupPartitions()
+
+			assert.Nil(t, err, "CleanupPartitions should succeed")
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCleanupKeepsUserComments(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	config.CleanupPolicy = partition.Detach
+	config.RenameDetached = true
+
+	removed, _ := config.GeneratePartition(workDate.AddDate(0, 0, -2))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+
+	testCases := []struct {
+		name    string
+		comment string
+		merged  string
+	}{
+		{
+			"Fields of a JSON comment are kept",
+			`{"owner":"billing"}`,
+			`{"detachedAt":"2024-06-15T00:00:00Z","lowerBound":"2024-06-13T00:00:00Z","originalName":"public.my_table_2024_06_13",` +
+				`"owner":"billing","parentTable":"public.my_table","upperBound":"2024-06-14T00:00:00Z"}`,
+		},
+		{"Text comment is not overwritten", "Loaded from the legacy system", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			removed.Comment = tc.comment
+
+			checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{removed, yesterdayPartition, currentPartition}), nil).Once()
+
+			if tc.merged != "" {
+				postgreSQLMock.On("DetachPartitionConcurrently", removed.Schema, removed.Name, removed.ParentTable).Return(nil).Once()
+				postgreSQLMock.On("SetTableComment", removed.Schema, removed.Name, tc.merged).Return(nil).Once()
+				postgreSQLMock.On("RenameTable", removed.Schema, removed.Name, "my_table_2024_06_13_detached_20240615").Return(nil).Once()
+			}
+
+			err := checker.CleanupPartitions()
+
+			if tc.merged != "" {
+				assert.Nil(t, err, "CleanupPartitions should succeed")
+			} else {
+				// The partition is kept attached
+				assert.ErrorIs(t, err, ppm.ErrPartitionCleanupFailed)
+			}
+
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCleanupDetachedNameTooLong(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.CleanupPolicy = partition.Detach
+	config.RenameDetached = true
+	config.Table = strings.Repeat("t", 40)
+
+	removed, _ := config.GeneratePartition(dayBeforeYesterday)
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	existing := []partition.Partition{removed, yesterdayPartition, currentPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("DetachPartitionConcurrently", removed.Schema, removed.Name, removed.ParentTable).Return(nil).Once()
+
+	err := checker.CleanupPartitions()
+
+	assert.ErrorIs(t, err, ppm.ErrPartitionCleanupFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
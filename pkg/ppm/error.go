@@ -4,14 +4,41 @@ import (
 	"errors"
 
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/retry"
 )
 
 const (
 	ObjectNotInPrerequisiteStatePostgreSQLErrorCode = "55000"
+	LockNotAvailablePostgreSQLErrorCode             = "55P03"
 )
 
+const maxLockAttempts = 3
+
 func isPostgreSQLErrorCode(err error, errorCode string) bool {
 	var pgErr *pgconn.PgError
 
 	return errors.As(err, &pgErr) && pgErr.Code == errorCode
 }
+
+// retryOnLockTimeout runs the statement again when it waited longer than the lock timeout, so it never blocks the table for long.
+// Other errors are not retried.
+func (p PPM) retryOnLockTimeout(action string, part partition.Partition, statement func() error) error {
+	var statementErr error
+
+	err := retry.WithRetry(maxLockAttempts, func(attempt int) error {
+		statementErr = statement()
+		if isPostgreSQLErrorCode(statementErr, LockNotAvailablePostgreSQLErrorCode) {
+			p.logger.Warn("Lock timeout while "+action, "schema", part.Schema, "table", part.Name, "attempt", attempt, "max_retries", maxLockAttempts)
+
+			return statementErr
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return statementErr
+}
@@ -0,0 +1,260 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+)
+
+var (
+	ErrPartitionExchangeFailed = errors.New("partition exchange failed")
+	ErrIncompatibleTable       = errors.New("table does not match the parent table")
+)
+
+// ExchangePartition replaces a partition by a prepared table with the same bounds.
+// The table must have the columns, check constraints and indexes of the parent table.
+// A check constraint matching the partition bounds is validated on the table before the swap,
+// so attaching the table does not scan it under lock, and the table replaces the partition in a single transaction.
+// The replaced table is dropped and the prepared table takes its name, unless keep is set or the partition is held.
+func (p PPM) ExchangePartition(name, partitionName, table string, keep bool) error {
+	config, err := p.getConfiguration(name)
+	if err != nil {
+		return err
+	}
+
+	partitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	for _, part := range partitions {
+		if part.Name != partitionName {
+			continue
+		}
+
+		err = p.exchangePartition(config, part, table, keep)
+		if err != nil {
+			p.logger.Error("Failed to exchange partition", "error", err, "schema", part.Schema, "table", part.Name, "replacement", table)
+
+			return fmt.Errorf("%w: %w", ErrPartitionExchangeFailed, err)
+		}
+
+		return nil
+	}
+
+	p.logger.Error("Partition not found", "schema", config.Schema, "table", partitionName, "parent_table", config.Table)
+
+	return fmt.Errorf("%w: %s", ErrPartitionNotFound, partitionName)
+}
+
+func (p PPM) exchangePartition(config partition.Configuration, part partition.Partition, table string, keep bool) error {
+	replacement := partition.Partition{
+		Schema:      part.Schema,
+		Name:        table,
+		ParentTable: part.ParentTable,
+		LowerBound:  part.LowerBound,
+		UpperBound:  part.UpperBound,
+	}
+
+	exists, err := p.db.IsTableExists(replacement.Schema, replacement.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check if table exists: %w", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrPartitionNotFound, replacement.QualifiedName())
+	}
+
+	attached, err := p.db.IsPartitionAttached(replacement.Schema, replacement.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check partition attachment status: %w", err)
+	}
+
+	if attached {
+		return fmt.Errorf("%w: %s is already a partition", ErrIncompatibleTable, replacement.QualifiedName())
+	}
+
+	err = p.validateReplacement(replacement)
+	if err != nil {
+		return err
+	}
+
+	_, partitionKey, err := p.db.GetPartitionSettings(part.Schema, part.ParentTable)
+	if err != nil {
+		return fmt.Errorf("failed to get partition settings: %w", err)
+	}
+
+	keyType, err := p.db.GetColumnDataType(part.Schema, part.ParentTable, partitionKey)
+	if err != nil {
+		return fmt.Errorf("failed to get partition key details: %w", err)
+	}
+
+	lowerBound, err := formatBound(keyType, part.LowerBound)
+	if err != nil {
+		return err
+	}
+
+	upperBound, err := formatBound(keyType, part.UpperBound)
+	if err != nil {
+		return err
+	}
+
+	// The constraint is validated while the table is not attached, it fails when rows are outside of the partition bounds
+	err = p.db.AddBoundsConstraint(replacement.Schema, replacement.Name, boundsConstraint, partitionKey, lowerBound, upperBound)
+	if err != nil {
+		return err
+	}
+
+	err = p.createRuleIndexes(config, replacement)
+	if err != nil {
+		p.dropBoundsConstraint(replacement)
+
+		return err
+	}
+
+	err = p.inheritHold([]partition.Partition{part}, replacement)
+	if err != nil {
+		p.dropBoundsConstraint(replacement)
+
+		return err
+	}
+
+	err = p.retryOnLockTimeout("replacing partitions", part, func() error {
+		return p.db.ReplacePartitions(part.Schema, part.ParentTable, []string{part.Name}, []postgresql.Attachment{
+			{Table: replacement.Name, LowerBound: lowerBound, UpperBound: upperBound},
+		})
+	})
+	if err != nil {
+		p.dropBoundsConstraint(replacement)
+
+		return err
+	}
+
+	p.logger.Info("Partition exchanged", "schema", part.Schema, "table", part.Name, "replacement", replacement.Name, "range", partition.Bounds(part.LowerBound, part.UpperBound))
+
+	p.dropBoundsConstraint(replacement)
+
+	err = p.db.SetPartitionReplicaIdentity(replacement.Schema, replacement.Name, replacement.ParentTable)
+	if err != nil {
+		return fmt.Errorf("failed to set replica identity: %w", err)
+	}
+
+	err = p.addToPublications(config, replacement)
+	if err != nil {
+		return err
+	}
+
+	if keep {
+		p.logger.Info("Original partition kept", "schema", part.Schema, "table", part.Name)
+
+		return nil
+	}
+
+	held, err := p.dropReplacedPartitions(config, []partition.Partition{part})
+	if err != nil {
+		return err
+	}
+
+	if len(held) > 0 {
+		p.logger.Warn("Original partition is held, the replacement keeps its name", "schema", replacement.Schema, "table", replacement.Name)
+
+		return nil
+	}
+
+	err = p.renamePartition(config, replacement, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", replacement.Name, part.Name, err)
+	}
+
+	return nil
+}
+
+// validateReplacement checks the table can be attached to the parent table without changes.
+// Indexes of the parent table missing on the table would be built under lock when it is attached.
+func (p PPM) validateReplacement(replacement partition.Partition) error {
+	var problems []string
+
+	parentColumns, err := p.db.ListColumns(replacement.Schema, replacement.ParentTable)
+	if err != nil {
+		return err
+	}
+
+	columns, err := p.db.ListColumns(replacement.Schema, replacement.Name)
+	if err != nil {
+		return err
+	}
+
+	problems = append(problems, columnProblems(parentColumns, columns)...)
+
+	parentConstraints, err := p.db.ListCheckConstraints(replacement.Schema, replacement.ParentTable)
+	if err != nil {
+		return err
+	}
+
+	constraints, err := p.db.ListCheckConstraints(replacement.Schema, replacement.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, constraint := range parentConstraints {
+		if !slices.Contains(constraints, constraint) {
+			problems = append(problems, fmt.Sprintf("missing check constraint %s", constraint))
+		}
+	}
+
+	parentIndexes, err := p.db.ListPartitionedIndexes(replacement.Schema, replacement.ParentTable)
+	if err != nil {
+		return err
+	}
+
+	indexes, err := p.db.ListTableIndexes(replacement.Schema, replacement.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range parentIndexes {
+		if !slices.ContainsFunc(indexes, func(i postgresql.PartitionedIndex) bool {
+			return i.Definition == index.Definition && i.Unique == index.Unique
+		}) {
+			problems = append(problems, fmt.Sprintf("missing index %s (%s)", index.Name, index.Definition))
+		}
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			p.logger.Warn("Table does not match the parent table", "schema", replacement.Schema, "table", replacement.Name, "parent_table", replacement.ParentTable, "problem", problem)
+		}
+
+		return fmt.Errorf("%w: %s", ErrIncompatibleTable, strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+// columnProblems compares the columns of a table with the columns of its parent table
+func columnProblems(parentColumns, columns []postgresql.Column) (problems []string) {
+	for _, parentColumn := range parentColumns {
+		i := slices.IndexFunc(columns, func(c postgresql.Column) bool { return c.Name == parentColumn.Name })
+
+		switch {
+		case i < 0:
+			problems = append(problems, fmt.Sprintf("missing column %s", parentColumn.Name))
+		case columns[i].Type != parentColumn.Type:
+			problems = append(problems, fmt.Sprintf("column %s is %s instead of %s", parentColumn.Name, columns[i].Type, parentColumn.Type))
+		case parentColumn.NotNull && !columns[i].NotNull:
+			problems = append(problems, fmt.Sprintf("column %s is nullable", parentColumn.Name))
+		}
+	}
+
+	for _, column := range columns {
+		if !slices.ContainsFunc(parentColumns, func(c postgresql.Column) bool { return c.Name == column.Name }) {
+			problems = append(problems, fmt.Sprintf("unexpected column %s", column.Name))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,180 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	exchangeColumns = []postgresql.Column{
+		{Name: "created_at", Type: "date", NotNull: true},
+		{Name: "id", Type: "bigint", NotNull: true},
+	}
+	exchangeConstraints = []string{"CHECK ((id > 0))"}
+	exchangeIndexes     = []postgresql.PartitionedIndex{
+		{Name: "my_table_pkey", Definition: "USING btree (id, created_at)", Unique: true},
+	}
+)
+
+// expectExchangeValidation expects the prepared table to be compared with the parent table
+func expectExchangeValidation(postgreSQLMock *mocks.PostgreSQLClient, config partition.Configuration, columns []postgresql.Column, constraints []string, indexes []postgresql.PartitionedIndex) {
+	postgreSQLMock.On("IsTableExists", "public", "my_table_rebuilt").Return(true, nil).Once()
+	postgreSQLMock.On("IsPartitionAttached", "public", "my_table_rebuilt").Return(false, nil).Once()
+	postgreSQLMock.On("ListColumns", "public", config.Table).Return(exchangeColumns, nil).Once()
+	postgreSQLMock.On("ListColumns", "public", "my_table_rebuilt").Return(columns, nil).Once()
+	postgreSQLMock.On("ListCheckConstraints", "public", config.Table).Return(exchangeConstraints, nil).Once()
+	postgreSQLMock.On("ListCheckConstraints", "public", "my_table_rebuilt").Return(constraints, nil).Once()
+	postgreSQLMock.On("ListPartitionedIndexes", "public", config.Table).Return(exchangeIndexes, nil).Once()
+	postgreSQLMock.On("ListTableIndexes", "public", "my_table_rebuilt").Return(indexes, nil).Once()
+}
+
+// expectExchangeBounds expects the bounds constraint to be added to the prepared table
+func expectExchangeBounds(postgreSQLMock *mocks.PostgreSQLClient, config partition.Configuration) {
+	postgreSQLMock.On("GetPartitionSettings", "public", config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", "public", config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("AddBoundsConstraint", "public", "my_table_rebuilt", "ppm_partition_bounds", config.PartitionKey, "2024-06-13", "2024-06-14").Return(nil).Once()
+}
+
+// expectExchangeSwap expects the prepared table to replace the partition
+func expectExchangeSwap(postgreSQLMock *mocks.PostgreSQLClient, config partition.Configuration, part partition.Partition) {
+	postgreSQLMock.On("ReplacePartitions", "public", config.Table, []string{part.Name},
+		[]postgresql.Attachment{{Table: "my_table_rebuilt", LowerBound: "2024-06-13", UpperBound: "2024-06-14"}}).Return(nil).Once()
+	postgreSQLMock.On("DropConstraint", "public", "my_table_rebuilt", "ppm_partition_bounds").Return(nil).Once()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", "public", "my_table_rebuilt", config.Table).Return(nil).Once()
+}
+
+func TestExchangePartition(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	part, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{part}), nil).Once()
+
+	// Extra check constraints and indexes are accepted
+	expectExchangeValidation(postgreSQLMock, config, exchangeColumns, append([]string{"CHECK ((id < 1000))"}, exchangeConstraints...),
+		append([]postgresql.PartitionedIndex{{Name: "my_table_rebuilt_id", Definition: "USING btree (id)"}}, exchangeIndexes...))
+	expectExchangeBounds(postgreSQLMock, config)
+	expectExchangeSwap(postgreSQLMock, config, part)
+	postgreSQLMock.On("DropTable", "public", part.Name).Return(nil).Once()
+	postgreSQLMock.On("ListPartitionedIndexes", "public", config.Table).Return(exchangeIndexes, nil).Once()
+	postgreSQLMock.On("ListTableIndexes", "public", "my_table_rebuilt").Return([]postgresql.PartitionedIndex{{Name: "my_table_rebuilt_my_table_pkey"}}, nil).Once()
+	postgreSQLMock.On("RenameTableAndIndexes", "public", "my_table_rebuilt", part.Name,
+		[]postgresql.IndexRename{{Name: "my_table_rebuilt_my_table_pkey", NewName: "my_table_2024_06_13_my_table_pkey"}}).Return(nil).Once()
+
+	err := checker.ExchangePartition("unittest", part.Name, "my_table_rebuilt", false)
+	assert.Nil(t, err, "ExchangePartition should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestExchangePartitionHeld(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	part, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+	part.Comment = `{"holdUntil":"2024-07-01T00:00:00Z","holdReason":"Case 42"}`
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Date(2024, 6, 20, 0, 0, 0, 0, time.UTC))
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{part}), nil).Once()
+	expectExchangeValidation(postgreSQLMock, config, exchangeColumns, exchangeConstraints, exchangeIndexes)
+	expectExchangeBounds(postgreSQLMock, config)
+
+	// The prepared table inherits the hold, its comment is kept
+	postgreSQLMock.On("GetTableComment", "public", "my_table_rebuilt").Return(`{"owner":"billing"}`, nil).Once()
+	postgreSQLMock.On("SetTableComment", "public", "my_table_rebuilt", `{"holdReason":"Case 42","holdUntil":"2024-07-01T00:00:00Z","owner":"billing"}`).Return(nil).Once()
+	expectExchangeSwap(postgreSQLMock, config, part)
+
+	// The held partition is neither dropped nor replaced by name
+
+	err := checker.ExchangePartition("unittest", part.Name, "my_table_rebuilt", false)
+	assert.Nil(t, err, "ExchangePartition should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestExchangePartitionSwapFailure(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	part, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{part}), nil).Once()
+	expectExchangeValidation(postgreSQLMock, config, exchangeColumns, exchangeConstraints, exchangeIndexes)
+	expectExchangeBounds(postgreSQLMock, config)
+
+	// The partition stays attached, the prepared table is left as it was
+	postgreSQLMock.On("ReplacePartitions", "public", config.Table, []string{part.Name},
+		[]postgresql.Attachment{{Table: "my_table_rebuilt", LowerBound: "2024-06-13", UpperBound: "2024-06-14"}}).Return(ErrFake).Once()
+	postgreSQLMock.On("DropConstraint", "public", "my_table_rebuilt", "ppm_partition_bounds").Return(nil).Once()
+
+	err := checker.ExchangePartition("unittest", part.Name, "my_table_rebuilt", true)
+	assert.ErrorIs(t, err, ppm.ErrPartitionExchangeFailed)
+	assert.ErrorIs(t, err, ErrFake)
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestExchangePartitionIncompatibleTable(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	part, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name        string
+		columns     []postgresql.Column
+		constraints []string
+		indexes     []postgresql.PartitionedIndex
+	}{
+		{
+			"Missing column",
+			exchangeColumns[:1],
+			exchangeConstraints,
+			exchangeIndexes,
+		},
+		{
+			"Unexpected column",
+			append([]postgresql.Column{{Name: "comment", Type: "text"}}, exchangeColumns...),
+			exchangeConstraints,
+			exchangeIndexes,
+		},
+		{
+			"Column type mismatch",
+			[]postgresql.Column{exchangeColumns[0], {Name: "id", Type: "integer", NotNull: true}},
+			exchangeConstraints,
+			exchangeIndexes,
+		},
+		{
+			"Nullable column",
+			[]postgresql.Column{exchangeColumns[0], {Name: "id", Type: "bigint"}},
+			exchangeConstraints,
+			exchangeIndexes,
+		},
+		{
+			"Missing check constraint",
+			exchangeColumns,
+			nil,
+			exchangeIndexes,
+		},
+		{
+			"Index with another definition",
+			exchangeColumns,
+			exchangeConstraints,
+			[]postgresql.PartitionedIndex{{Name: "my_table_rebuilt_pkey", Definition: "USING btree (id)", Unique: true}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{part}), nil).Once()
+			expectExchangeValidation(postgreSQLMock, config, tc.columns, tc.constraints, tc.indexes)
+
+			err := checker.ExchangePartition("unittest", part.Name, "my_table_rebuilt", false)
+			assert.ErrorIs(t, err, ppm.ErrIncompatibleTable)
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
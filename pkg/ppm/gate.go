@@ -0,0 +1,39 @@
+package ppm
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+// applyRetentionGate returns the partitions approved by the retention gate query: the oldest removable partitions, up to the first one not approved.
+// The other partitions are skipped until the query returns true, so no hole is left in the history, and are listed in the logs to be followed up.
+func (p PPM) applyRetentionGate(config partition.Configuration, removable []partition.Partition) ([]partition.Partition, error) {
+	if config.RetentionGate == "" {
+		return removable, nil
+	}
+
+	removable = slices.Clone(removable)
+	slices.SortFunc(removable, func(a, b partition.Partition) int {
+		return a.LowerBound.Compare(b.LowerBound)
+	})
+
+	for i, part := range removable {
+		value, err := p.db.EvaluateQuery(config.RetentionGate, part.LowerBound, part.UpperBound)
+		if err != nil {
+			return nil, fmt.Errorf("could not evaluate retention gate on %s: %w", part.Name, err)
+		}
+
+		if result, ok := value.(bool); ok && result {
+			continue
+		}
+
+		p.logger.Info("Partition not approved by the retention gate, skip it and newer partitions", "schema", part.Schema, "table", part.Name, "result", value)
+		p.logger.Warn("Partitions waiting for the retention gate", "schema", config.Schema, "table", config.Table, "partitions", partitionNames(removable[i:]))
+
+		return removable[:i], nil
+	}
+
+	return removable, nil
+}
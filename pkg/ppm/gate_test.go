@@ -0,0 +1,61 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+const retentionGate = "SELECT exported FROM etl.export_status WHERE period = $1"
+
+func TestRetentionGate(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	config.RetentionGate = retentionGate
+
+	exported, _ := config.GeneratePartition(workDate.AddDate(0, 0, -4))
+	notExported, _ := config.GeneratePartition(workDate.AddDate(0, 0, -3))
+	newer, _ := config.GeneratePartition(workDate.AddDate(0, 0, -2))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{newer, notExported, exported, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("EvaluateQuery", retentionGate, exported.LowerBound, exported.UpperBound).Return(true, nil).Once()
+	postgreSQLMock.On("EvaluateQuery", retentionGate, notExported.LowerBound, notExported.UpperBound).Return(nil, nil).Once()
+
+	// Newer partitions wait for the oldest one, so no hole is left in the history
+
+	postgreSQLMock.On("DetachPartitionConcurrently", exported.Schema, exported.Name, exported.ParentTable).Return(nil).Once()
+	postgreSQLMock.On("DropTable", exported.Schema, exported.Name).Return(nil).Once()
+
+	assert.Nil(t, checker.CleanupPartitions(), "Cleanup should skip partitions not approved by the retention gate")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestRetentionGateFailure(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	config.RetentionGate = retentionGate
+
+	expired, _ := config.GeneratePartition(workDate.AddDate(0, 0, -2))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	existing := []partition.Partition{expired, yesterdayPartition, currentPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("EvaluateQuery", retentionGate, expired.LowerBound, expired.UpperBound).Return(nil, ErrFake).Once()
+
+	assert.ErrorIs(t, checker.CleanupPartitions(), ppm.ErrPartitionCleanupFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
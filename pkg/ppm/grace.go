@@ -0,0 +1,181 @@
+package ppm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+// listDetachedPartitions returns the tables detached from the parent table by cleanup, with their metadata
+func (p PPM) listDetachedPartitions(config partition.Configuration) (detached []partition.Partition, err error) {
+	parentTable := fmt.Sprintf("%s.%s", config.Schema, config.Table)
+
+	tables, err := p.db.ListTablesByComment(partition.DetachedFromMarker(parentTable))
+	if err != nil {
+		return nil, fmt.Errorf("could not list detached partitions: %w", err)
+	}
+
+	for _, table := range tables {
+		metadata := partition.ParseMetadata(table.Comment)
+		if metadata.ParentTable != parentTable {
+			continue
+		}
+
+		detached = append(detached, partition.Partition{
+			Schema:      table.Schema,
+			Name:        table.Name,
+			ParentTable: config.Table,
+			LowerBound:  metadata.LowerBound,
+			UpperBound:  metadata.UpperBound,
+			Metadata:    metadata,
+			Comment:     table.Comment,
+		})
+	}
+
+	return detached, nil
+}
+
+// dropExpiredPartitions drops the partitions detached by cleanup for longer than the grace period
+func (p PPM) dropExpiredPartitions(config partition.Configuration) error {
+	detached, err := p.listDetachedPartitions(config)
+	if err != nil {
+		return err
+	}
+
+	dropFailed := false
+
+	for _, part := range detached {
+		if part.Metadata.DetachedAt.IsZero() {
+			p.logger.Warn("Detached partition has no detach date, skip", "schema", part.Schema, "table", part.Name)
+
+			continue
+		}
+
+		expiresAt := part.Metadata.DetachedAt.AddDate(0, 0, config.GracePeriod)
+		if p.workDate.Before(expiresAt) {
+			p.logger.Debug("Detached partition is in its grace period, skip", "schema", part.Schema, "table", part.Name, "expires_at", expiresAt)
+
+			continue
+		}
+
+		if held, reason := config.GetHold(part, p.workDate); held {
+			p.logger.Info("Detached partition is held, skip", "schema", part.Schema, "table", part.Name, "reason", reason, "hold_until", part.Metadata.HoldUntil)
+
+			continue
+		}
+
+		err = p.DeletePartition(part)
+		if err != nil {
+			dropFailed = true
+
+			p.logger.Error("Failed to delete partition", "schema", part.Schema, "table", part.Name, "error", err)
+
+			continue
+		}
+
+		p.logger.Info("Partition deleted after grace period", "schema", part.Schema, "table", part.Name, "parent_table", part.Metadata.ParentTable, "detached_at", part.Metadata.DetachedAt)
+	}
+
+	if dropFailed {
+		return ErrPartitionCleanupFailed
+	}
+
+	return nil
+}
+
+// ReattachPartition attaches a partition detached by cleanup again, under its original name and schema.
+// The partition is held for holdDays days, so cleanup does not detach it again while it is outside of the retention.
+func (p PPM) ReattachPartition(name, partitionName string, holdDays int) error {
+	config, err := p.getConfiguration(name)
+	if err != nil {
+		return err
+	}
+
+	detached, err := p.listDetachedPartitions(config)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range detached {
+		originalSchema, originalName, _ := strings.Cut(table.Metadata.OriginalName, ".")
+		if partitionName != table.Name && partitionName != originalName && partitionName != table.Metadata.OriginalName {
+			continue
+		}
+
+		err = p.reattachPartition(config, table, originalSchema, originalName, holdDays)
+		if err != nil {
+			p.logger.Error("Failed to reattach partition", "error", err, "schema", table.Schema, "table", table.Name)
+
+			return fmt.Errorf("failed to reattach partition: %w", err)
+		}
+
+		return nil
+	}
+
+	p.logger.Error("Detached partition not found", "table", partitionName, "parent_table", config.Table)
+
+	return fmt.Errorf("%w: %s", ErrPartitionNotFound, partitionName)
+}
+
+func (p PPM) reattachPartition(config partition.Configuration, table partition.Partition, originalSchema, originalName string, holdDays int) error {
+	if table.Schema != originalSchema {
+		err := p.db.SetTableSchema(table.Schema, table.Name, originalSchema)
+		if err != nil {
+			return fmt.Errorf("failed to move partition back: %w", err)
+		}
+	}
+
+	if table.Name != originalName {
+		err := p.db.RenameTable(originalSchema, table.Name, originalName)
+		if err != nil {
+			return fmt.Errorf("failed to rename partition back: %w", err)
+		}
+	}
+
+	part := partition.Partition{
+		Schema:      originalSchema,
+		Name:        originalName,
+		ParentTable: config.Table,
+		LowerBound:  table.LowerBound,
+		UpperBound:  table.UpperBound,
+		Metadata:    partition.Metadata{HoldUntil: p.workDate.AddDate(0, 0, holdDays)},
+		Comment:     table.Comment,
+	}
+
+	err := p.setMetadata(part)
+	if err != nil {
+		return err
+	}
+
+	// The table already exists, CreatePartition only attaches it
+	err = p.CreatePartition(config, part)
+	if err != nil {
+		return p.restoreDetachMetadata(part, table.Comment, err)
+	}
+
+	p.logger.Info("Partition reattached", "schema", part.Schema, "table", part.Name, "parent_table", part.ParentTable, "hold_until", part.Metadata.HoldUntil)
+
+	return nil
+}
+
+// restoreDetachMetadata sets the detach metadata back on a table that could not be attached again,
+// so it is still listed as detached, and reattached or dropped later.
+func (p PPM) restoreDetachMetadata(part partition.Partition, comment string, cause error) error {
+	attached, err := p.db.IsPartitionAttached(part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("%w, and the partition attachment status could not be checked: %w", cause, err)
+	}
+
+	// An attached partition keeps its hold, the detach metadata would let cleanup drop it
+	if attached {
+		return cause
+	}
+
+	err = p.db.SetTableComment(part.Schema, part.Name, comment)
+	if err != nil {
+		return fmt.Errorf("%w, and the detach metadata could not be restored: %w", cause, err)
+	}
+
+	return cause
+}
@@ -0,0 +1,112 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupWithGracePeriod(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.GracePeriod = 7
+
+	removed, _ := config.GeneratePartition(workDate.AddDate(0, 0, -2))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{removed, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	detached := []postgresql.TableResult{
+		{Schema: "public", Name: "my_table_2024_06_01", Comment: `{"detachedAt":"2024-06-08T00:00:00Z","parentTable":"public.my_table"}`},
+		{Schema: "public", Name: "my_table_2024_06_05", Comment: `{"detachedAt":"2024-06-12T00:00:00Z","parentTable":"public.my_table"}`},
+		{Schema: "public", Name: "my_table_2024_05_01", Comment: `{"parentTable":"public.my_table","originalName":"public.my_table_2024_05_01"}`},
+		{Schema: "public", Name: "other_table_2024_06_01", Comment: `{"detachedAt":"2024-06-01T00:00:00Z","parentTable":"public.my_table_2"}`},
+	}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	// Only the partition detached for 7 days is dropped, the one without detach date is kept
+	postgreSQLMock.On("ListTablesByComment", `"parentTable":"public.my_table"`).Return(detached, nil).Once()
+	postgreSQLMock.On("DropTable", "public", "my_table_2024_06_01").Return(nil).Once()
+
+	// Partition outside of the retention is detached and recorded, but not dropped
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("DetachPartitionConcurrently", removed.Schema, removed.Name, removed.ParentTable).Return(nil).Once()
+	postgreSQLMock.On("SetTableComment", removed.Schema, removed.Name,
+		`{"detachedAt":"2024-06-15T00:00:00Z","parentTable":"public.my_table","originalName":"public.my_table_2024_06_13",`+
+			`"lowerBound":"2024-06-13T00:00:00Z","upperBound":"2024-06-14T00:00:00Z"}`).Return(nil).Once()
+
+	err := checker.CleanupPartitions()
+
+	assert.Nil(t, err, "CleanupPartitions should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestReattachPartition(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.GracePeriod = 7
+
+	detached := []postgresql.TableResult{
+		{
+			Schema: "archive",
+			Name:   "my_table_2024_06_10_detached_20240613",
+			Comment: `{"detachedAt":"2024-06-13T00:00:00Z","parentTable":"public.my_table","originalName":"public.my_table_2024_06_10",` +
+				`"lowerBound":"2024-06-10T00:00:00Z","upperBound":"2024-06-11T00:00:00Z"}`,
+		},
+	}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListTablesByComment", `"parentTable":"public.my_table"`).Return(detached, nil)
+
+	postgreSQLMock.On("SetTableSchema", "archive", "my_table_2024_06_10_detached_20240613", "public").Return(nil).Once()
+	postgreSQLMock.On("RenameTable", "public", "my_table_2024_06_10_detached_20240613", "my_table_2024_06_10").Return(nil).Once()
+	postgreSQLMock.On("SetTableComment", "public", "my_table_2024_06_10", `{"holdUntil":"2024-06-22T00:00:00Z"}`).Return(nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", "public", config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", "public", config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("IsTableExists", "public", "my_table_2024_06_10").Return(true, nil).Once()
+	postgreSQLMock.On("IsPartitionAttached", "public", "my_table_2024_06_10").Return(false, nil).Once()
+	postgreSQLMock.On("AttachPartition", "public", "my_table_2024_06_10", config.Table, "2024-06-10", "2024-06-11").Return(nil).Once()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", "public", "my_table_2024_06_10", config.Table).Return(nil).Once()
+
+	err := checker.ReattachPartition("unittest", "my_table_2024_06_10", 7)
+	assert.Nil(t, err, "ReattachPartition should succeed")
+
+	err = checker.ReattachPartition("unittest", "my_table_2024_06_11", 7)
+	assert.ErrorIs(t, err, ppm.ErrPartitionNotFound)
+
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestReattachPartitionAttachFailure(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.GracePeriod = 7
+
+	comment := `{"detachedAt":"2024-06-13T00:00:00Z","parentTable":"public.my_table","originalName":"public.my_table_2024_06_10",` +
+		`"lowerBound":"2024-06-10T00:00:00Z","upperBound":"2024-06-11T00:00:00Z"}`
+	detached := []postgresql.TableResult{{Schema: "public", Name: "my_table_2024_06_10", Comment: comment}}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListTablesByComment", `"parentTable":"public.my_table"`).Return(detached, nil).Once()
+	postgreSQLMock.On("SetTableComment", "public", "my_table_2024_06_10", `{"holdUntil":"2024-06-22T00:00:00Z"}`).Return(nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", "public", config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", "public", config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("IsTableExists", "public", "my_table_2024_06_10").Return(true, nil).Once()
+	postgreSQLMock.On("IsPartitionAttached", "public", "my_table_2024_06_10").Return(false, nil).Twice()
+	postgreSQLMock.On("AttachPartition", "public", "my_table_2024_06_10", config.Table, "2024-06-10", "2024-06-11").Return(ErrFake).Times(3)
+
+	// The detach metadata is restored, so the table is still listed as detached
+	postgreSQLMock.On("SetTableComment", "public", "my_table_2024_06_10", comment).Return(nil).Once()
+
+	err := checker.ReattachPartition("unittest", "my_table_2024_06_10", 7)
+	assert.ErrorIs(t, err, ErrFake)
+	postgreSQLMock.AssertExpectations(t)
+}
@@ -0,0 +1,49 @@
+package ppm
+
+import (
+	"fmt"
+	"time"
+)
+
+// HoldPartition records a hold in the partition comment, so cleanup keeps the partition until the given date.
+// Both attached partitions and partitions detached by cleanup can be held.
+func (p PPM) HoldPartition(name, partitionName string, until time.Time, reason string) error {
+	config, err := p.getConfiguration(name)
+	if err != nil {
+		return err
+	}
+
+	partitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	detached, err := p.listDetachedPartitions(config)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range append(partitions, detached...) {
+		if part.Name != partitionName {
+			continue
+		}
+
+		part.Metadata.HoldUntil = until
+		part.Metadata.HoldReason = reason
+
+		err = p.setMetadata(part)
+		if err != nil {
+			p.logger.Error("Failed to hold partition", "error", err, "schema", part.Schema, "table", part.Name)
+
+			return fmt.Errorf("failed to hold partition: %w", err)
+		}
+
+		p.logger.Info("Partition held", "schema", part.Schema, "table", part.Name, "hold_until", until, "reason", reason)
+
+		return nil
+	}
+
+	p.logger.Error("Partition not found", "schema", config.Schema, "table", partitionName, "parent_table", config.Table)
+
+	return fmt.Errorf("%w: %s", ErrPartitionNotFound, partitionName)
+}
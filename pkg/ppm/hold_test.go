@@ -0,0 +1,87 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigurationHeldPartitionsAreKept(t *testing.T) {
+	heldDate := dayBeforeYesterday.AddDate(0, 0, -10)
+
+	config := OneDayPartitionConfiguration
+	config.Holds = []partition.Hold{{From: heldDate, To: heldDate, Reason: "Case 42"}}
+
+	held, _ := config.GeneratePartition(heldDate)
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+	existing := []partition.Partition{held, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil)
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+
+	assert.Nil(t, checker.CleanupPartitions(), "Cleanup should keep held partitions")
+	assert.Nil(t, checker.CheckPartitions(), "Check should consider held partitions as intended")
+
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestHeldDetachedPartitionsAreNotDropped(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.GracePeriod = 7
+	config.Holds = []partition.Hold{{From: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)}}
+
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{yesterdayPartition, currentPartition, tomorrowPartition}
+
+	detached := []postgresql.TableResult{
+		{
+			Schema: "public", Name: "my_table_2024_05_20",
+			Comment: `{"detachedAt":"2024-05-27T00:00:00Z","parentTable":"public.my_table","lowerBound":"2024-05-20T00:00:00Z","upperBound":"2024-05-21T00:00:00Z"}`,
+		},
+		{
+			Schema: "public", Name: "my_table_2024_06_01",
+			Comment: `{"holdUntil":"2024-07-01T00:00:00Z","detachedAt":"2024-06-02T00:00:00Z","parentTable":"public.my_table","lowerBound":"2024-06-01T00:00:00Z","upperBound":"2024-06-02T00:00:00Z"}`,
+		},
+	}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListTablesByComment", `"parentTable":"public.my_table"`).Return(detached, nil).Once()
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	assert.Nil(t, checker.CleanupPartitions(), "Cleanup should keep held detached partitions")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestHoldPartition(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	until := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	currentPartition, _ := config.GeneratePartition(today)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{currentPartition}), nil)
+	postgreSQLMock.On("ListTablesByComment", `"parentTable":"public.my_table"`).Return([]postgresql.TableResult{}, nil)
+	postgreSQLMock.On("SetTableComment", currentPartition.Schema, currentPartition.Name, `{"holdUntil":"2025-01-01T00:00:00Z","holdReason":"Case 42"}`).Return(nil).Once()
+
+	err := checker.HoldPartition("unittest", currentPartition.Name, until, "Case 42")
+	assert.Nil(t, err, "HoldPartition should succeed")
+
+	err = checker.HoldPartition("unittest", "unknown", until, "Case 42")
+	assert.ErrorIs(t, err, ppm.ErrPartitionNotFound)
+
+	postgreSQLMock.AssertExpectations(t)
+}
@@ -0,0 +1,237 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+)
+
+var ErrIndexLifecycleFailed = errors.New("at least one partition index could not be managed")
+
+// ManageIndexes creates the indexes declared by index rules on partitions entering their age stage,
+// and drops them from partitions leaving it
+func (p PPM) ManageIndexes() error {
+	indexesFailed := false
+
+	for name, config := range p.partitions {
+		if len(config.Indexes) == 0 {
+			continue
+		}
+
+		p.logger.Info("Managing partition indexes", "partition", name)
+
+		if err := p.manageIndexesFor(config); err != nil {
+			p.logger.Error("Failed to manage partition indexes", "error", err, "schema", config.Schema, "table", config.Table)
+
+			indexesFailed = true
+		}
+	}
+
+	if indexesFailed {
+		return ErrIndexLifecycleFailed
+	}
+
+	p.logger.Info("All partition indexes are managed")
+
+	return nil
+}
+
+func (p PPM) manageIndexesFor(config partition.Configuration) error {
+	foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	for _, part := range foundPartitions {
+		for _, rule := range config.Indexes {
+			inStage, err := config.IsInAgeRange(part, p.workDate, rule.MinAge, rule.MaxAge)
+			if err != nil {
+				return fmt.Errorf("could not evaluate partition age: %w", err)
+			}
+
+			if inStage {
+				err = p.ensureIndex(part, rule)
+			} else {
+				err = p.removeIndex(part, rule)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p PPM) ensureIndex(part partition.Partition, rule partition.IndexRule) error {
+	indexName := rule.IndexName(part)
+
+	err := p.migrateLegacyIndex(part, rule.Name)
+	if err != nil {
+		return err
+	}
+
+	status, err := p.db.GetIndexStatus(part.Schema, indexName)
+	if err != nil {
+		return fmt.Errorf("failed to get index status: %w", err)
+	}
+
+	if status.Exists && status.Valid {
+		p.logger.Debug("Index already exists, skip", "schema", part.Schema, "table", part.Name, "index", indexName)
+
+		return nil
+	}
+
+	if status.Exists {
+		// An interrupted concurrent build leaves an invalid index that CREATE INDEX IF NOT EXISTS would not replace
+		p.logger.Warn("Invalid index found, rebuild it", "schema", part.Schema, "table", part.Name, "index", indexName)
+
+		err = p.db.DropIndexConcurrently(part.Schema, indexName)
+		if err != nil {
+			return fmt.Errorf("failed to drop invalid index: %w", err)
+		}
+	}
+
+	err = p.db.CreateIndexConcurrently(part.Schema, part.Name, indexName, rule.Definition)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+
+	p.logger.Info("Index created", "schema", part.Schema, "table", part.Name, "index", indexName)
+
+	return nil
+}
+
+func (p PPM) removeIndex(part partition.Partition, rule partition.IndexRule) error {
+	indexName := rule.IndexName(part)
+
+	err := p.migrateLegacyIndex(part, rule.Name)
+	if err != nil {
+		return err
+	}
+
+	status, err := p.db.GetIndexStatus(part.Schema, indexName)
+	if err != nil {
+		return fmt.Errorf("failed to get index status: %w", err)
+	}
+
+	if !status.Exists {
+		return nil
+	}
+
+	if status.Attached {
+		// Indexes attached to a partitioned index are managed by the parent table
+		p.logger.Warn("Index is attached to a partitioned index, skip", "schema", part.Schema, "table", part.Name, "index", indexName)
+
+		return nil
+	}
+
+	err = p.db.DropIndexConcurrently(part.Schema, indexName)
+	if err != nil {
+		return fmt.Errorf("failed to drop index: %w", err)
+	}
+
+	p.logger.Info("Index dropped", "schema", part.Schema, "table", part.Name, "index", indexName)
+
+	return nil
+}
+
+// createRuleIndexes creates the indexes of the rules matching the age of the partition, on a table built to replace partitions.
+// They get the partition index names, as if the table had always been the partition.
+func (p PPM) createRuleIndexes(config partition.Configuration, part partition.Partition) error {
+	for _, rule := range config.Indexes {
+		inStage, err := config.IsInAgeRange(part, p.workDate, rule.MinAge, rule.MaxAge)
+		if err != nil {
+			return fmt.Errorf("could not evaluate partition age: %w", err)
+		}
+
+		if !inStage {
+			continue
+		}
+
+		err = p.ensureIndex(part, rule)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// partitionIndexRenames returns the indexes created by PPM on the partition, from index rules or rolled out from the parent table,
+// with their name once the partition is renamed. Other indexes of the partition keep their name.
+func (p PPM) partitionIndexRenames(config partition.Configuration, part partition.Partition, newName string) ([]postgresql.IndexRename, error) {
+	names := make([]string, 0, len(config.Indexes))
+	for _, rule := range config.Indexes {
+		names = append(names, rule.Name)
+	}
+
+	parentIndexes, err := p.db.ListPartitionedIndexes(config.Schema, config.Table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parent table indexes: %w", err)
+	}
+
+	for _, index := range parentIndexes {
+		names = append(names, index.Name)
+	}
+
+	indexes, err := p.db.ListTableIndexes(part.Schema, part.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partition indexes: %w", err)
+	}
+
+	renamed := part
+	renamed.Name = newName
+
+	var renames []postgresql.IndexRename
+
+	for _, index := range indexes {
+		for _, name := range names {
+			if index.Name == partition.PartitionIndexName(part, name) || index.Name == partition.LegacyPartitionIndexName(part, name) {
+				renames = append(renames, postgresql.IndexRename{Name: index.Name, NewName: partition.PartitionIndexName(renamed, name)})
+
+				break
+			}
+		}
+	}
+
+	return renames, nil
+}
+
+// migrateLegacyIndex renames the index created by PPM on the partition before index names were shortened,
+// so it is managed under its shortened name instead of being created again.
+// Only indexes of the partition are considered, as truncated names of several partitions may be the same.
+func (p PPM) migrateLegacyIndex(part partition.Partition, name string) error {
+	indexName := partition.PartitionIndexName(part, name)
+
+	legacyName := partition.LegacyPartitionIndexName(part, name)
+	if legacyName == indexName {
+		return nil
+	}
+
+	indexes, err := p.db.ListTableIndexes(part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list partition indexes: %w", err)
+	}
+
+	hasIndex := func(name string) bool {
+		return slices.ContainsFunc(indexes, func(index postgresql.PartitionedIndex) bool { return index.Name == name })
+	}
+
+	if !hasIndex(legacyName) || hasIndex(indexName) {
+		return nil
+	}
+
+	err = p.db.RenameIndex(part.Schema, legacyName, indexName)
+	if err != nil {
+		return fmt.Errorf("failed to rename index: %w", err)
+	}
+
+	p.logger.Info("Index renamed to its shortened name", "schema", part.Schema, "table", part.Name, "index", legacyName, "new_name", indexName)
+
+	return nil
+}
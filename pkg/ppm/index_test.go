@@ -0,0 +1,95 @@
+package ppm_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManageIndexes(t *testing.T) {
+	hotRule := partition.IndexRule{Name: "pending", Definition: "(status) WHERE status = 'pending'", MinAge: 0, MaxAge: 1}
+	historyRule := partition.IndexRule{Name: "brin", Definition: "USING brin (created_at)", MinAge: 1}
+
+	config := OneDayPartitionConfiguration
+	config.Indexes = []partition.IndexRule{hotRule, historyRule}
+
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	existing := []partition.Partition{yesterdayPartition, currentPartition, tomorrowPartition}
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	// Yesterday partition left the hot stage and entered the history stage
+	postgreSQLMock.On("GetIndexStatus", config.Schema, hotRule.IndexName(yesterdayPartition)).Return(postgresql.IndexStatus{Exists: true, Valid: true}, nil).Once()
+	postgreSQLMock.On("DropIndexConcurrently", config.Schema, hotRule.IndexName(yesterdayPartition)).Return(nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, historyRule.IndexName(yesterdayPartition)).Return(postgresql.IndexStatus{}, nil).Once()
+	postgreSQLMock.On("CreateIndexConcurrently", config.Schema, yesterdayPartition.Name, historyRule.IndexName(yesterdayPartition), historyRule.Definition).Return(nil).Once()
+
+	// Current partition has an invalid hot index that must be rebuilt
+	postgreSQLMock.On("GetIndexStatus", config.Schema, hotRule.IndexName(currentPartition)).Return(postgresql.IndexStatus{Exists: true, Valid: false}, nil).Once()
+	postgreSQLMock.On("DropIndexConcurrently", config.Schema, hotRule.IndexName(currentPartition)).Return(nil).Once()
+	postgreSQLMock.On("CreateIndexConcurrently", config.Schema, currentPartition.Name, hotRule.IndexName(currentPartition), hotRule.Definition).Return(nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, historyRule.IndexName(currentPartition)).Return(postgresql.IndexStatus{}, nil).Once()
+
+	// Future partition already has its hot index, and an attached index named like the history rule is kept
+	postgreSQLMock.On("GetIndexStatus", config.Schema, hotRule.IndexName(tomorrowPartition)).Return(postgresql.IndexStatus{Exists: true, Valid: true}, nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, historyRule.IndexName(tomorrowPartition)).Return(postgresql.IndexStatus{Exists: true, Valid: true, Attached: true}, nil).Once()
+
+	err := checker.ManageIndexes()
+
+	assert.Nil(t, err, "ManageIndexes should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestManageIndexesFailure(t *testing.T) {
+	rule := partition.IndexRule{Name: "brin", Definition: "USING brin (created_at)"}
+
+	config := OneDayPartitionConfiguration
+	config.Indexes = []partition.IndexRule{rule}
+
+	currentPartition, _ := config.GeneratePartition(today)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{currentPartition}), nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, rule.IndexName(currentPartition)).Return(postgresql.IndexStatus{}, nil).Once()
+	postgreSQLMock.On("CreateIndexConcurrently", config.Schema, currentPartition.Name, rule.IndexName(currentPartition), rule.Definition).Return(ErrFake).Once()
+
+	err := checker.ManageIndexes()
+
+	assert.ErrorIs(t, err, ppm.ErrIndexLifecycleFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestManageIndexesMigratesLegacyNames(t *testing.T) {
+	rule := partition.IndexRule{Name: "brin", Definition: "USING brin (created_at)"}
+
+	config := OneDayPartitionConfiguration
+	config.Table = strings.Repeat("t", 60)
+	config.Indexes = []partition.IndexRule{rule}
+
+	currentPartition, _ := config.GeneratePartition(today)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	// The index was created before index names were shortened, PostgreSQL truncated its name
+	legacyName := partition.LegacyPartitionIndexName(currentPartition, rule.Name)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{currentPartition}), nil).Once()
+	postgreSQLMock.On("ListTableIndexes", config.Schema, currentPartition.Name).Return([]postgresql.PartitionedIndex{{Name: legacyName}}, nil).Once()
+	postgreSQLMock.On("RenameIndex", config.Schema, legacyName, rule.IndexName(currentPartition)).Return(nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, rule.IndexName(currentPartition)).Return(postgresql.IndexStatus{Exists: true, Valid: true}, nil).Once()
+
+	err := checker.ManageIndexes()
+
+	assert.Nil(t, err, "ManageIndexes should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
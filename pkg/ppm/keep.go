@@ -0,0 +1,95 @@
+package ppm
+
+import (
+	"strings"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/expression"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+const hoursPerDay = 24
+
+// isKept returns true when a hold, a retention tier or the keepIf expression keeps the partition past the retention, with the reason.
+// keepIf is nil when it is not configured. A partition whose keepIf evaluation fails is kept, and the failure is logged.
+func (p PPM) isKept(config partition.Configuration, keepIf *expression.Expression, part partition.Partition) (kept bool, reason string) {
+	if kept, reason := config.IsKept(part, p.workDate); kept {
+		return true, reason
+	}
+
+	if keepIf == nil {
+		return false, ""
+	}
+
+	kept, err := keepIf.EvaluateBool(p.partitionVariables(config, part))
+	if err != nil {
+		p.logger.Error("Failed to evaluate keepIf expression, partition is kept", "schema", part.Schema, "table", part.Name, "error", err)
+
+		return true, "keepIf evaluation failed"
+	}
+
+	if kept {
+		return true, "keepIf " + keepIf.String()
+	}
+
+	return false, ""
+}
+
+// withoutKeptPartitions removes kept partitions lying outside of the expected range.
+// They are kept on purpose, for example after a restore or for a legal hold, and must neither be cleaned up nor be considered as a gap.
+// The keepIf expression is only evaluated by cleanup, check and provisioning pass a nil keepIf and ignore expired partitions instead.
+func (p PPM) withoutKeptPartitions(config partition.Configuration, keepIf *expression.Expression, partitions []partition.Partition, expectedRange partition.PartitionRange) (result []partition.Partition) {
+	for _, part := range partitions {
+		outside := !part.UpperBound.After(expectedRange.LowerBound) || !part.LowerBound.Before(expectedRange.UpperBound)
+
+		if outside {
+			if kept, reason := p.isKept(config, keepIf, part); kept {
+				p.logger.Info("Partition is kept, skip", "schema", part.Schema, "table", part.Name, "reason", reason, "hold_until", part.Metadata.HoldUntil)
+
+				continue
+			}
+		}
+
+		result = append(result, part)
+	}
+
+	if keepIf == nil && config.KeepIf != "" {
+		// Expired partitions may be kept by keepIf, only cleanup decides
+		_, result = splitExpiredPartitions(result, expectedRange)
+	}
+
+	return result
+}
+
+// partitionVariables resolves the variables of the keepIf expression for a partition.
+// Size, row estimate and query results are only fetched when the expression uses them.
+func (p PPM) partitionVariables(config partition.Configuration, part partition.Partition) expression.Resolver {
+	return func(name string) (any, error) {
+		switch name {
+		case "lower_bound":
+			return part.LowerBound, nil
+		case "upper_bound":
+			return part.UpperBound, nil
+		case "year":
+			return part.LowerBound.Year(), nil
+		case "quarter":
+			return (int(part.LowerBound.Month())-1)/3 + 1, nil
+		case "month":
+			return int(part.LowerBound.Month()), nil
+		case "age":
+			// Days elapsed since the end of the partition
+			return int(p.workDate.Sub(part.UpperBound).Hours() / hoursPerDay), nil
+		case "rows":
+			return p.db.GetRowEstimate(part.Schema, part.Name)
+		case "size":
+			return p.db.GetTableSize(part.Schema, part.Name)
+		}
+
+		if queryName, found := strings.CutPrefix(name, "query."); found {
+			if query, ok := config.Queries[queryName]; ok {
+				return p.db.EvaluateQuery(query, part.LowerBound, part.UpperBound)
+			}
+		}
+
+		return nil, expression.ErrUnknownVariable
+	}
+}
@@ -0,0 +1,96 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepIfExpression(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	notExported := "SELECT NOT EXISTS (SELECT 1 FROM exports WHERE period_start = $1)"
+
+	config := OneDayPartitionConfiguration
+	config.KeepIf = "(quarter == 4 && age < 7 * 365) || (rows > 0 && query.not_exported)"
+	config.Queries = map[string]string{"not_exported": notExported}
+
+	fourthQuarter, _ := config.GeneratePartition(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+	notExportedPartition, _ := config.GeneratePartition(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	exported, _ := config.GeneratePartition(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC))
+	emptyPartition, _ := config.GeneratePartition(time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{fourthQuarter, notExportedPartition, exported, emptyPartition, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	// Variables are only resolved when the expression needs them
+	postgreSQLMock.On("GetRowEstimate", notExportedPartition.Schema, notExportedPartition.Name).Return(int64(1000), nil).Once()
+	postgreSQLMock.On("EvaluateQuery", notExported, notExportedPartition.LowerBound, notExportedPartition.UpperBound).Return(true, nil).Once()
+	postgreSQLMock.On("GetRowEstimate", exported.Schema, exported.Name).Return(int64(1000), nil).Once()
+	postgreSQLMock.On("EvaluateQuery", notExported, exported.LowerBound, exported.UpperBound).Return(false, nil).Once()
+	postgreSQLMock.On("GetRowEstimate", emptyPartition.Schema, emptyPartition.Name).Return(int64(0), nil).Once()
+
+	for _, p := range []partition.Partition{exported, emptyPartition} {
+		postgreSQLMock.On("DetachPartitionConcurrently", p.Schema, p.Name, p.ParentTable).Return(nil).Once()
+		postgreSQLMock.On("DropTable", p.Schema, p.Name).Return(nil).Once()
+	}
+
+	assert.Nil(t, checker.CleanupPartitions(), "Cleanup should keep partitions matching the keepIf expression")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestKeepIfExpressionFailure(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	failing := "SELECT count(*) > 0 FROM invoices WHERE issued_at >= $1"
+
+	config := OneDayPartitionConfiguration
+	config.KeepIf = "query.open_invoices"
+	config.Queries = map[string]string{"open_invoices": failing}
+
+	expired, _ := config.GeneratePartition(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{expired, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	// Partitions are never removed when the expression can't be evaluated, the failure is logged
+	postgreSQLMock.On("EvaluateQuery", failing, expired.LowerBound, expired.UpperBound).Return(nil, ErrFake).Once()
+
+	assert.Nil(t, checker.CleanupPartitions(), "CleanupPartitions should skip the partition")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestCheckDoesNotEvaluateKeepIf(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	config.KeepIf = "rows > 0"
+
+	expired, _ := config.GeneratePartition(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{expired, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	// Expired partitions may be kept by keepIf, check and provisioning leave them to cleanup
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil)
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil)
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Twice()
+
+	assert.Nil(t, checker.CheckPartitions(), "CheckPartitions should succeed")
+	assert.Nil(t, checker.ProvisioningPartitions(), "ProvisioningPartitions should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
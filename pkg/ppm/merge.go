@@ -0,0 +1,347 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+)
+
+// Check constraint added to a table before attaching it, so the attach does not scan the table under lock
+const boundsConstraint = "ppm_partition_bounds"
+
+var (
+	ErrPartitionMergeFailed = errors.New("partition merge failed")
+	ErrInvalidMerge         = errors.New("partitions can't be merged")
+)
+
+// MergePartitions replaces the partitions within the into interval partition containing the date by a single partition.
+// The partitions are set read-only, and their rows are copied to the merged table in batches ordered by the partition key.
+// The merged table then replaces the partitions in a single transaction, so queries never miss rows.
+// The original tables are dropped, unless keep is set or the partitions are held.
+func (p PPM) MergePartitions(name string, at time.Time, into partition.Interval, batchSize int, keep bool) error {
+	config, err := p.getConfiguration(name)
+	if err != nil {
+		return err
+	}
+
+	coarser := config
+	coarser.Interval = into
+
+	merged, err := coarser.GeneratePartition(at)
+	if err != nil {
+		return fmt.Errorf("could not generate partition: %w", err)
+	}
+
+	partitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	sources, err := mergedPartitions(merged, partitions)
+	if err == nil {
+		err = p.mergePartitions(config, merged, sources, batchSize, keep)
+	}
+
+	if err != nil {
+		p.logger.Error("Failed to merge partitions", "error", err, "schema", merged.Schema, "table", merged.Name)
+
+		return fmt.Errorf("%w: %w", ErrPartitionMergeFailed, err)
+	}
+
+	return nil
+}
+
+// mergedPartitions returns the partitions within the bounds of the merged partition, sorted by bounds.
+// They must cover the merged partition without gaps.
+func mergedPartitions(merged partition.Partition, partitions []partition.Partition) ([]partition.Partition, error) {
+	var sources []partition.Partition
+
+	for _, part := range partitions {
+		if !part.Overlaps(merged) {
+			continue
+		}
+
+		if part.LowerBound.Before(merged.LowerBound) || part.UpperBound.After(merged.UpperBound) {
+			return nil, fmt.Errorf("%w: %s crosses the bounds of %s", ErrInvalidMerge, part.Name, merged.Name)
+		}
+
+		sources = append(sources, part)
+	}
+
+	if len(sources) < 2 { //nolint:mnd // a merge replaces at least two partitions
+		return nil, fmt.Errorf("%w: less than two partitions within the bounds of %s", ErrInvalidMerge, merged.Name)
+	}
+
+	slices.SortFunc(sources, func(a, b partition.Partition) int {
+		return a.LowerBound.Compare(b.LowerBound)
+	})
+
+	coveredUntil := merged.LowerBound
+
+	for _, part := range sources {
+		if !part.LowerBound.Equal(coveredUntil) {
+			return nil, fmt.Errorf("%w: no partition for %s", ErrInvalidMerge, partition.Bounds(coveredUntil, part.LowerBound))
+		}
+
+		coveredUntil = part.UpperBound
+	}
+
+	if !coveredUntil.Equal(merged.UpperBound) {
+		return nil, fmt.Errorf("%w: no partition for %s", ErrInvalidMerge, partition.Bounds(coveredUntil, merged.UpperBound))
+	}
+
+	return sources, nil
+}
+
+func (p PPM) mergePartitions(config partition.Configuration, merged partition.Partition, sources []partition.Partition, batchSize int, keep bool) error {
+	exists, err := p.db.IsTableExists(merged.Schema, merged.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check if table exists: %w", err)
+	}
+
+	if exists {
+		return fmt.Errorf("%w: table %s already exists", ErrNameCollision, merged.Name)
+	}
+
+	// Partitions are read-only during the merge, so rows can't be written to a partition once it is copied
+	frozen, err := p.setReadOnly(sources)
+	if err != nil {
+		p.unsetReadOnly(frozen)
+
+		return err
+	}
+
+	err = p.buildMergedPartition(config, merged, sources, batchSize)
+	if err != nil {
+		p.dropCreatedTables([]partition.Partition{merged})
+		p.unsetReadOnly(frozen)
+
+		return err
+	}
+
+	p.logger.Info("Partitions merged", "schema", merged.Schema, "table", merged.Name, "partitions", len(sources))
+
+	p.dropBoundsConstraint(merged)
+
+	err = p.db.SetPartitionReplicaIdentity(merged.Schema, merged.Name, merged.ParentTable)
+	if err != nil {
+		return fmt.Errorf("failed to set replica identity: %w", err)
+	}
+
+	err = p.addToPublications(config, merged)
+	if err != nil {
+		return err
+	}
+
+	p.unsetReadOnly(frozen)
+
+	if keep {
+		p.logger.Info("Original partitions kept", "schema", merged.Schema, "partitions", partitionNames(sources))
+
+		return nil
+	}
+
+	_, err = p.dropReplacedPartitions(config, sources)
+
+	return err
+}
+
+// buildMergedPartition copies the rows of the partitions to the merged table, then replaces the partitions by the merged table
+func (p PPM) buildMergedPartition(config partition.Configuration, merged partition.Partition, sources []partition.Partition, batchSize int) error {
+	_, partitionKey, err := p.db.GetPartitionSettings(merged.Schema, merged.ParentTable)
+	if err != nil {
+		return fmt.Errorf("failed to get partition settings: %w", err)
+	}
+
+	keyType, err := p.db.GetColumnDataType(merged.Schema, merged.ParentTable, partitionKey)
+	if err != nil {
+		return fmt.Errorf("failed to get partition key details: %w", err)
+	}
+
+	var expected int64
+
+	for _, source := range sources {
+		count, err := p.db.CountRows(source.Schema, source.Name)
+		if err != nil {
+			return fmt.Errorf("failed to count rows: %w", err)
+		}
+
+		expected += count
+	}
+
+	err = p.db.CreateTableLikeTable(merged.Schema, merged.Name, merged.ParentTable)
+	if err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	var total int64
+
+	for i, source := range sources {
+		lowerBound, err := formatBound(keyType, source.LowerBound)
+		if err != nil {
+			return err
+		}
+
+		upperBound, err := formatBound(keyType, source.UpperBound)
+		if err != nil {
+			return err
+		}
+
+		copied, err := p.copyRowsInBatches(source, merged, partitionKey, lowerBound, upperBound, batchSize, total)
+		total += copied
+
+		if err != nil {
+			return err
+		}
+
+		p.logger.Info("Partition copied", "schema", source.Schema, "table", source.Name, "target", merged.Name, "progress", fmt.Sprintf("%d/%d partitions, %d/%d rows", i+1, len(sources), total, expected))
+	}
+
+	if total != expected {
+		return fmt.Errorf("%w: %d rows in the partitions, %d rows copied", ErrRowCountMismatch, expected, total)
+	}
+
+	lowerBound, err := formatBound(keyType, merged.LowerBound)
+	if err != nil {
+		return err
+	}
+
+	upperBound, err := formatBound(keyType, merged.UpperBound)
+	if err != nil {
+		return err
+	}
+
+	err = p.db.AddBoundsConstraint(merged.Schema, merged.Name, boundsConstraint, partitionKey, lowerBound, upperBound)
+	if err != nil {
+		return err
+	}
+
+	err = p.createRuleIndexes(config, merged)
+	if err != nil {
+		return err
+	}
+
+	err = p.inheritHold(sources, merged)
+	if err != nil {
+		return err
+	}
+
+	return p.retryOnLockTimeout("replacing partitions", merged, func() error {
+		return p.db.ReplacePartitions(merged.Schema, merged.ParentTable, partitionNames(sources), []postgresql.Attachment{
+			{Table: merged.Name, LowerBound: lowerBound, UpperBound: upperBound},
+		})
+	})
+}
+
+// setReadOnly sets the partitions read-only, and returns the partitions that were not read-only already
+func (p PPM) setReadOnly(partitions []partition.Partition) (frozen []partition.Partition, err error) {
+	for _, part := range partitions {
+		readOnly, err := p.db.IsTableReadOnly(part.Schema, part.Name)
+		if err != nil {
+			return frozen, fmt.Errorf("failed to check read-only status: %w", err)
+		}
+
+		if readOnly {
+			continue
+		}
+
+		err = p.db.SetTableReadOnly(part.Schema, part.Name)
+		if err != nil {
+			return frozen, fmt.Errorf("failed to set partition read-only: %w", err)
+		}
+
+		frozen = append(frozen, part)
+	}
+
+	return frozen, nil
+}
+
+// unsetReadOnly removes the read-only protection set by setReadOnly
+func (p PPM) unsetReadOnly(frozen []partition.Partition) {
+	for _, part := range frozen {
+		err := p.db.UnsetTableReadOnly(part.Schema, part.Name)
+		if err != nil {
+			p.logger.Warn("Failed to unset partition read-only", "error", err, "schema", part.Schema, "table", part.Name)
+		}
+	}
+}
+
+// dropBoundsConstraint drops the constraint added to attach the table, it is redundant with the partition bounds once attached
+func (p PPM) dropBoundsConstraint(part partition.Partition) {
+	err := p.db.DropConstraint(part.Schema, part.Name, boundsConstraint)
+	if err != nil {
+		p.logger.Warn("Failed to drop bounds constraint", "error", err, "schema", part.Schema, "table", part.Name)
+	}
+}
+
+// dropReplacedPartitions drops the partitions replaced by a split, a merge or an exchange.
+// Held partitions are kept as standalone tables, and returned.
+func (p PPM) dropReplacedPartitions(config partition.Configuration, replaced []partition.Partition) (held []partition.Partition, err error) {
+	for _, part := range replaced {
+		if isHeld, reason := config.GetHold(part, p.workDate); isHeld {
+			p.logger.Warn("Original partition is held, keep it", "schema", part.Schema, "table", part.Name, "reason", reason, "hold_until", part.Metadata.HoldUntil)
+
+			held = append(held, part)
+
+			continue
+		}
+
+		err = p.DeletePartition(part)
+		if err != nil {
+			return held, fmt.Errorf("failed to drop the original partition %s: %w", part.Name, err)
+		}
+	}
+
+	return held, nil
+}
+
+// strictestHold returns the comment hold of the partitions lasting the longest at the date, an empty metadata when none is held
+func strictestHold(partitions []partition.Partition, at time.Time) (hold partition.Metadata) {
+	for _, part := range partitions {
+		if part.Metadata.IsHeld(at) && part.Metadata.HoldUntil.After(hold.HoldUntil) {
+			hold = partition.Metadata{HoldUntil: part.Metadata.HoldUntil, HoldReason: part.Metadata.HoldReason}
+		}
+	}
+
+	return hold
+}
+
+// inheritHold records the comment hold of the replaced partitions on the table replacing them,
+// so cleanup keeps the rows the hold was set for. Other content of the table comment is kept.
+func (p PPM) inheritHold(replaced []partition.Partition, part partition.Partition) error {
+	hold := strictestHold(replaced, p.workDate)
+	if hold == (partition.Metadata{}) {
+		return nil
+	}
+
+	comment, err := p.db.GetTableComment(part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get table comment: %w", err)
+	}
+
+	part.Comment = comment
+	part.Metadata = hold
+
+	err = p.setMetadata(part)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Info("Hold of the replaced partitions recorded", "schema", part.Schema, "table", part.Name, "hold_until", hold.HoldUntil, "reason", hold.HoldReason)
+
+	return nil
+}
+
+func partitionNames(partitions []partition.Partition) []string {
+	names := make([]string, 0, len(partitions))
+
+	for _, part := range partitions {
+		names = append(names, part.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,190 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// weekPartitions returns the daily partitions of the week from 2024-06-10 to 2024-06-16, merged into my_table_2024_w24
+func weekPartitions(config partition.Configuration) []partition.Partition {
+	var partitions []partition.Partition
+
+	for day := range 7 {
+		part, _ := config.GeneratePartition(time.Date(2024, 6, 10+day, 0, 0, 0, 0, time.UTC))
+		partitions = append(partitions, part)
+	}
+
+	return partitions
+}
+
+func partitionNames(partitions []partition.Partition) []string {
+	var names []string
+
+	for _, part := range partitions {
+		names = append(names, part.Name)
+	}
+
+	return names
+}
+
+// expectMergeCopy expects the partitions to be set read-only and copied to the merged table, one row less than counted for the last partition when missing is set
+func expectMergeCopy(postgreSQLMock *mocks.PostgreSQLClient, config partition.Configuration, sources []partition.Partition, missing bool) {
+	postgreSQLMock.On("IsTableExists", "public", "my_table_2024_w24").Return(false, nil).Once()
+
+	for _, source := range sources {
+		postgreSQLMock.On("IsTableReadOnly", "public", source.Name).Return(false, nil).Once()
+		postgreSQLMock.On("SetTableReadOnly", "public", source.Name).Return(nil).Once()
+		postgreSQLMock.On("CountRows", "public", source.Name).Return(int64(10), nil).Once()
+	}
+
+	postgreSQLMock.On("GetPartitionSettings", "public", config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", "public", config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("CreateTableLikeTable", "public", "my_table_2024_w24", config.Table).Return(nil).Once()
+
+	for i, source := range sources {
+		lowerBound := source.LowerBound.Format(time.DateOnly)
+		upperBound := source.UpperBound.Format(time.DateOnly)
+
+		copied := int64(10)
+		if missing && i == len(sources)-1 {
+			copied = 9
+		}
+
+		postgreSQLMock.On("GetBatchUpperBound", "public", source.Name, config.PartitionKey, lowerBound, upperBound, 100).Return("", nil).Once()
+		postgreSQLMock.On("CopyRows", "public", source.Name, "public", "my_table_2024_w24", config.PartitionKey, lowerBound, upperBound).Return(copied, nil).Once()
+	}
+}
+
+// expectMergeIndexes expects the index rules to be built on the merged table before it is attached
+func expectMergeIndexes(postgreSQLMock *mocks.PostgreSQLClient, config partition.Configuration) {
+	merged := partition.Partition{Schema: "public", Name: "my_table_2024_w24"}
+
+	for _, rule := range config.Indexes {
+		postgreSQLMock.On("GetIndexStatus", "public", rule.IndexName(merged)).Return(postgresql.IndexStatus{}, nil).Once()
+		postgreSQLMock.On("CreateIndexConcurrently", "public", merged.Name, rule.IndexName(merged), rule.Definition).Return(nil).Once()
+	}
+}
+
+func TestMergePartitions(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Indexes = []partition.IndexRule{{Name: "brin", Definition: "USING brin (created_at)"}}
+	sources := weekPartitions(config)
+	lockTimeout := &pgconn.PgError{Code: ppm.LockNotAvailablePostgreSQLErrorCode}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, sources), nil).Once()
+	expectMergeCopy(postgreSQLMock, config, sources, false)
+	postgreSQLMock.On("AddBoundsConstraint", "public", "my_table_2024_w24", "ppm_partition_bounds", config.PartitionKey, "2024-06-10", "2024-06-17").Return(nil).Once()
+	expectMergeIndexes(postgreSQLMock, config)
+
+	// The swap is retried on lock timeout
+	postgreSQLMock.On("ReplacePartitions", "public", config.Table, partitionNames(sources), []postgresql.Attachment{{Table: "my_table_2024_w24", LowerBound: "2024-06-10", UpperBound: "2024-06-17"}}).Return(lockTimeout).Once()
+	postgreSQLMock.On("ReplacePartitions", "public", config.Table, partitionNames(sources), []postgresql.Attachment{{Table: "my_table_2024_w24", LowerBound: "2024-06-10", UpperBound: "2024-06-17"}}).Return(nil).Once()
+
+	postgreSQLMock.On("DropConstraint", "public", "my_table_2024_w24", "ppm_partition_bounds").Return(nil).Once()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", "public", "my_table_2024_w24", config.Table).Return(nil).Once()
+
+	for _, source := range sources {
+		postgreSQLMock.On("UnsetTableReadOnly", "public", source.Name).Return(nil).Once()
+		postgreSQLMock.On("DropTable", "public", source.Name).Return(nil).Once()
+	}
+
+	err := checker.MergePartitions("unittest", time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC), partition.Weekly, 100, false)
+	assert.Nil(t, err, "MergePartitions should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestMergePartitionsKeepsHeldSources(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	sources := weekPartitions(config)
+	sources[2].Comment = `{"holdUntil":"2024-07-01T00:00:00Z","holdReason":"Case 41"}`
+	sources[4].Comment = `{"holdUntil":"2024-08-01T00:00:00Z","holdReason":"Case 42"}`
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Date(2024, 6, 20, 0, 0, 0, 0, time.UTC))
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, sources), nil).Once()
+	expectMergeCopy(postgreSQLMock, config, sources, false)
+	postgreSQLMock.On("AddBoundsConstraint", "public", "my_table_2024_w24", "ppm_partition_bounds", config.PartitionKey, "2024-06-10", "2024-06-17").Return(nil).Once()
+
+	// The merged partition inherits the hold lasting the longest before it replaces the held partitions
+	postgreSQLMock.On("GetTableComment", "public", "my_table_2024_w24").Return("", nil).Once()
+	postgreSQLMock.On("SetTableComment", "public", "my_table_2024_w24", `{"holdUntil":"2024-08-01T00:00:00Z","holdReason":"Case 42"}`).Return(nil).Once()
+	postgreSQLMock.On("ReplacePartitions", "public", config.Table, partitionNames(sources), []postgresql.Attachment{{Table: "my_table_2024_w24", LowerBound: "2024-06-10", UpperBound: "2024-06-17"}}).Return(nil).Once()
+	postgreSQLMock.On("DropConstraint", "public", "my_table_2024_w24", "ppm_partition_bounds").Return(nil).Once()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", "public", "my_table_2024_w24", config.Table).Return(nil).Once()
+
+	// Held partitions are kept as standalone tables
+	for i, source := range sources {
+		postgreSQLMock.On("UnsetTableReadOnly", "public", source.Name).Return(nil).Once()
+
+		if i != 2 && i != 4 {
+			postgreSQLMock.On("DropTable", "public", source.Name).Return(nil).Once()
+		}
+	}
+
+	err := checker.MergePartitions("unittest", time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC), partition.Weekly, 100, false)
+	assert.Nil(t, err, "MergePartitions should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestMergePartitionsRowCountMismatch(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	sources := weekPartitions(config)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, sources), nil).Once()
+	expectMergeCopy(postgreSQLMock, config, sources, true)
+
+	// The merged table is dropped and the partitions are writable again
+	postgreSQLMock.On("IsTableExists", "public", "my_table_2024_w24").Return(true, nil).Once()
+	postgreSQLMock.On("DropTable", "public", "my_table_2024_w24").Return(nil).Once()
+
+	for _, source := range sources {
+		postgreSQLMock.On("UnsetTableReadOnly", "public", source.Name).Return(nil).Once()
+	}
+
+	err := checker.MergePartitions("unittest", time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC), partition.Weekly, 100, true)
+	assert.ErrorIs(t, err, ppm.ErrPartitionMergeFailed)
+	assert.ErrorIs(t, err, ppm.ErrRowCountMismatch)
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestMergePartitionsInvalid(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	sources := weekPartitions(config)
+	at := time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC)
+
+	crossing := sources[6]
+	crossing.UpperBound = crossing.UpperBound.AddDate(0, 0, 1)
+
+	testCases := []struct {
+		name       string
+		partitions []partition.Partition
+	}{
+		{"Gap between partitions", append(append([]partition.Partition{}, sources[:3]...), sources[4:]...)},
+		{"Missing last partition", sources[:6]},
+		{"Partition crossing the merged bounds", append(append([]partition.Partition{}, sources[:6]...), crossing)},
+		{"Single partition", sources[:1]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, tc.partitions), nil).Once()
+
+			err := checker.MergePartitions("unittest", at, partition.Weekly, 100, false)
+			assert.ErrorIs(t, err, ppm.ErrInvalidMerge)
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
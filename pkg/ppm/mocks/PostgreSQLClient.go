@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	io "io"
+
 	postgresql "github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
 	mock "github.com/stretchr/testify/mock"
 
@@ -14,6 +16,62 @@ type PostgreSQLClient struct {
 	mock.Mock
 }
 
+// AddBoundsConstraint provides a mock function with given fields: schema, table, constraint, column, lowerBound, upperBound
+func (_m *PostgreSQLClient) AddBoundsConstraint(schema string, table string, constraint string, column string, lowerBound string, upperBound string) error {
+	ret := _m.Called(schema, table, constraint, column, lowerBound, upperBound)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string) error); ok {
+		r0 = rf(schema, table, constraint, column, lowerBound, upperBound)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddIndexConstraint provides a mock function with given fields: schema, table, index, constraint
+func (_m *PostgreSQLClient) AddIndexConstraint(schema string, table string, index string, constraint string) error {
+	ret := _m.Called(schema, table, index, constraint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(schema, table, index, constraint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddTableToPublication provides a mock function with given fields: publication, schema, table
+func (_m *PostgreSQLClient) AddTableToPublication(publication string, schema string, table string) error {
+	ret := _m.Called(publication, schema, table)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(publication, schema, table)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AttachIndex provides a mock function with given fields: schema, parentIndex, index
+func (_m *PostgreSQLClient) AttachIndex(schema string, parentIndex string, index string) error {
+	ret := _m.Called(schema, parentIndex, index)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(schema, parentIndex, index)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AttachPartition provides a mock function with given fields: schema, table, parent, lowerBound, upperBound
 func (_m *PostgreSQLClient) AttachPartition(schema string, table string, parent string, lowerBound string, upperBound string) error {
 	ret := _m.Called(schema, table, parent, lowerBound, upperBound)
@@ -28,13 +86,151 @@ func (_m *PostgreSQLClient) AttachPartition(schema string, table string, parent
 	return r0
 }
 
-// SetPartitionReplicaIdentity provides a mock function with given fields: schema, table, parent
-func (_m *PostgreSQLClient) SetPartitionReplicaIdentity(schema string, table string, parent string) error {
-	ret := _m.Called(schema, table, parent)
+// AttachPartitionWithDefaultRows provides a mock function with given fields: schema, table, parent, defaultSchema, defaultTable, column, lowerBound, upperBound
+func (_m *PostgreSQLClient) AttachPartitionWithDefaultRows(schema string, table string, parent string, defaultSchema string, defaultTable string, column string, lowerBound string, upperBound string) error {
+	ret := _m.Called(schema, table, parent, defaultSchema, defaultTable, column, lowerBound, upperBound)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(schema, table, parent)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string, string, string) error); ok {
+		r0 = rf(schema, table, parent, defaultSchema, defaultTable, column, lowerBound, upperBound)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CopyRows provides a mock function with given fields: sourceSchema, sourceTable, targetSchema, targetTable, column, lowerBound, upperBound
+func (_m *PostgreSQLClient) CopyRows(sourceSchema string, sourceTable string, targetSchema string, targetTable string, column string, lowerBound string, upperBound string) (int64, error) {
+	ret := _m.Called(sourceSchema, sourceTable, targetSchema, targetTable, column, lowerBound, upperBound)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string, string) (int64, error)); ok {
+		return rf(sourceSchema, sourceTable, targetSchema, targetTable, column, lowerBound, upperBound)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string, string) int64); ok {
+		r0 = rf(sourceSchema, sourceTable, targetSchema, targetTable, column, lowerBound, upperBound)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string, string, string, string) error); ok {
+		r1 = rf(sourceSchema, sourceTable, targetSchema, targetTable, column, lowerBound, upperBound)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CopyTableFrom provides a mock function with given fields: schema, table, format, r
+func (_m *PostgreSQLClient) CopyTableFrom(schema string, table string, format string, r io.Reader) (int64, error) {
+	ret := _m.Called(schema, table, format, r)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, io.Reader) (int64, error)); ok {
+		return rf(schema, table, format, r)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, io.Reader) int64); ok {
+		r0 = rf(schema, table, format, r)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, io.Reader) error); ok {
+		r1 = rf(schema, table, format, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CopyTableTo provides a mock function with given fields: schema, table, format, w
+func (_m *PostgreSQLClient) CopyTableTo(schema string, table string, format string, w io.Writer) (int64, error) {
+	ret := _m.Called(schema, table, format, w)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, io.Writer) (int64, error)); ok {
+		return rf(schema, table, format, w)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, io.Writer) int64); ok {
+		r0 = rf(schema, table, format, w)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, io.Writer) error); ok {
+		r1 = rf(schema, table, format, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountRows provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) CountRows(schema string, table string) (int64, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (int64, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) int64); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateIndexConcurrently provides a mock function with given fields: schema, table, index, definition
+func (_m *PostgreSQLClient) CreateIndexConcurrently(schema string, table string, index string, definition string) error {
+	ret := _m.Called(schema, table, index, definition)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(schema, table, index, definition)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateIndexOnParent provides a mock function with given fields: schema, table, index, definition
+func (_m *PostgreSQLClient) CreateIndexOnParent(schema string, table string, index string, definition string) error {
+	ret := _m.Called(schema, table, index, definition)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(schema, table, index, definition)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTableFromDefinition provides a mock function with given fields: definition
+func (_m *PostgreSQLClient) CreateTableFromDefinition(definition string) error {
+	ret := _m.Called(definition)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(definition)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -56,6 +252,44 @@ func (_m *PostgreSQLClient) CreateTableLikeTable(schema string, table string, pa
 	return r0
 }
 
+// CreateUniqueIndexConcurrently provides a mock function with given fields: schema, table, index, definition
+func (_m *PostgreSQLClient) CreateUniqueIndexConcurrently(schema string, table string, index string, definition string) error {
+	ret := _m.Called(schema, table, index, definition)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(schema, table, index, definition)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRowsBefore provides a mock function with given fields: schema, table, column, value, limit
+func (_m *PostgreSQLClient) DeleteRowsBefore(schema string, table string, column string, value string, limit int) (int64, error) {
+	ret := _m.Called(schema, table, column, value, limit)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, int) (int64, error)); ok {
+		return rf(schema, table, column, value, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string, int) int64); ok {
+		r0 = rf(schema, table, column, value, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string, int) error); ok {
+		r1 = rf(schema, table, column, value, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DetachPartitionConcurrently provides a mock function with given fields: schema, table, parent
 func (_m *PostgreSQLClient) DetachPartitionConcurrently(schema string, table string, parent string) error {
 	ret := _m.Called(schema, table, parent)
@@ -70,6 +304,34 @@ func (_m *PostgreSQLClient) DetachPartitionConcurrently(schema string, table str
 	return r0
 }
 
+// DropConstraint provides a mock function with given fields: schema, table, constraint
+func (_m *PostgreSQLClient) DropConstraint(schema string, table string, constraint string) error {
+	ret := _m.Called(schema, table, constraint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(schema, table, constraint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DropIndexConcurrently provides a mock function with given fields: schema, index
+func (_m *PostgreSQLClient) DropIndexConcurrently(schema string, index string) error {
+	ret := _m.Called(schema, index)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(schema, index)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DropTable provides a mock function with given fields: schema, table
 func (_m *PostgreSQLClient) DropTable(schema string, table string) error {
 	ret := _m.Called(schema, table)
@@ -84,6 +346,46 @@ func (_m *PostgreSQLClient) DropTable(schema string, table string) error {
 	return r0
 }
 
+// DropTableFromPublication provides a mock function with given fields: publication, schema, table
+func (_m *PostgreSQLClient) DropTableFromPublication(publication string, schema string, table string) error {
+	ret := _m.Called(publication, schema, table)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(publication, schema, table)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EvaluateQuery provides a mock function with given fields: query, lowerBound, upperBound
+func (_m *PostgreSQLClient) EvaluateQuery(query string, lowerBound time.Time, upperBound time.Time) (any, error) {
+	ret := _m.Called(query, lowerBound, upperBound)
+
+	var r0 any
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) (any, error)); ok {
+		return rf(query, lowerBound, upperBound)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) any); ok {
+		r0 = rf(query, lowerBound, upperBound)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(any)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
+		r1 = rf(query, lowerBound, upperBound)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FinalizePartitionDetach provides a mock function with given fields: schema, table, parent
 func (_m *PostgreSQLClient) FinalizePartitionDetach(schema string, table string, parent string) error {
 	ret := _m.Called(schema, table, parent)
@@ -98,6 +400,30 @@ func (_m *PostgreSQLClient) FinalizePartitionDetach(schema string, table string,
 	return r0
 }
 
+// GetBatchUpperBound provides a mock function with given fields: schema, table, column, lowerBound, upperBound, limit
+func (_m *PostgreSQLClient) GetBatchUpperBound(schema string, table string, column string, lowerBound string, upperBound string, limit int) (string, error) {
+	ret := _m.Called(schema, table, column, lowerBound, upperBound, limit)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, int) (string, error)); ok {
+		return rf(schema, table, column, lowerBound, upperBound, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, int) string); ok {
+		r0 = rf(schema, table, column, lowerBound, upperBound, limit)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string, string, int) error); ok {
+		r1 = rf(schema, table, column, lowerBound, upperBound, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetColumnDataType provides a mock function with given fields: schema, table, column
 func (_m *PostgreSQLClient) GetColumnDataType(schema string, table string, column string) (postgresql.ColumnType, error) {
 	ret := _m.Called(schema, table, column)
@@ -122,23 +448,23 @@ func (_m *PostgreSQLClient) GetColumnDataType(schema string, table string, colum
 	return r0, r1
 }
 
-// GetEngineVersion provides a mock function with given fields:
-func (_m *PostgreSQLClient) GetEngineVersion() (int64, error) {
-	ret := _m.Called()
+// GetColumnsDefinition provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) GetColumnsDefinition(schema string, table string) (string, error) {
+	ret := _m.Called(schema, table)
 
-	var r0 int64
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return rf(schema, table)
 	}
-	if rf, ok := ret.Get(0).(func() int64); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(schema, table)
 	} else {
-		r0 = ret.Get(0).(int64)
+		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -146,8 +472,8 @@ func (_m *PostgreSQLClient) GetEngineVersion() (int64, error) {
 	return r0, r1
 }
 
-// GetPartitionSettings provides a mock function with given fields: schema, table
-func (_m *PostgreSQLClient) GetPartitionSettings(schema string, table string) (string, string, error) {
+// GetDefaultPartition provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) GetDefaultPartition(schema string, table string) (string, string, error) {
 	ret := _m.Called(schema, table)
 
 	var r0 string
@@ -177,19 +503,19 @@ func (_m *PostgreSQLClient) GetPartitionSettings(schema string, table string) (s
 	return r0, r1, r2
 }
 
-// GetServerTime provides a mock function with given fields:
-func (_m *PostgreSQLClient) GetServerTime() (time.Time, error) {
+// GetEngineVersion provides a mock function with given fields:
+func (_m *PostgreSQLClient) GetEngineVersion() (int64, error) {
 	ret := _m.Called()
 
-	var r0 time.Time
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (time.Time, error)); ok {
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
 		return rf()
 	}
-	if rf, ok := ret.Get(0).(func() time.Time); ok {
+	if rf, ok := ret.Get(0).(func() int64); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(time.Time)
+		r0 = ret.Get(0).(int64)
 	}
 
 	if rf, ok := ret.Get(1).(func() error); ok {
@@ -201,9 +527,232 @@ func (_m *PostgreSQLClient) GetServerTime() (time.Time, error) {
 	return r0, r1
 }
 
-// IsPartitionAttached provides a mock function with given fields: schema, table
-func (_m *PostgreSQLClient) IsPartitionAttached(schema string, table string) (bool, error) {
-	ret := _m.Called(schema, table)
+// GetIndexStatus provides a mock function with given fields: schema, index
+func (_m *PostgreSQLClient) GetIndexStatus(schema string, index string) (postgresql.IndexStatus, error) {
+	ret := _m.Called(schema, index)
+
+	var r0 postgresql.IndexStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (postgresql.IndexStatus, error)); ok {
+		return rf(schema, index)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) postgresql.IndexStatus); ok {
+		r0 = rf(schema, index)
+	} else {
+		r0 = ret.Get(0).(postgresql.IndexStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, index)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPartitionSettings provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) GetPartitionSettings(schema string, table string) (string, string, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string) (string, string, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) string); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string) error); ok {
+		r2 = rf(schema, table)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRowEstimate provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) GetRowEstimate(schema string, table string) (int64, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (int64, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) int64); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServerTime provides a mock function with given fields:
+func (_m *PostgreSQLClient) GetServerTime() (time.Time, error) {
+	ret := _m.Called()
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (time.Time, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTableComment provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) GetTableComment(schema string, table string) (string, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTableSize provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) GetTableSize(schema string, table string) (int64, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (int64, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) int64); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasRowsFrom provides a mock function with given fields: schema, table, column, value
+func (_m *PostgreSQLClient) HasRowsFrom(schema string, table string, column string, value string) (bool, error) {
+	ret := _m.Called(schema, table, column, value)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) (bool, error)); ok {
+		return rf(schema, table, column, value)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, string) bool); ok {
+		r0 = rf(schema, table, column, value)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, string) error); ok {
+		r1 = rf(schema, table, column, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsIndexPartitionAttached provides a mock function with given fields: schema, parentIndex, table
+func (_m *PostgreSQLClient) IsIndexPartitionAttached(schema string, parentIndex string, table string) (bool, error) {
+	ret := _m.Called(schema, parentIndex, table)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
+		return rf(schema, parentIndex, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
+		r0 = rf(schema, parentIndex, table)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(schema, parentIndex, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsPartitionAttached provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) IsPartitionAttached(schema string, table string) (bool, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (bool, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsTableEmpty provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) IsTableEmpty(schema string, table string) (bool, error) {
+	ret := _m.Called(schema, table)
 
 	var r0 bool
 	var r1 error
@@ -249,6 +798,210 @@ func (_m *PostgreSQLClient) IsTableExists(schema string, table string) (bool, er
 	return r0, r1
 }
 
+// IsTableInPublication provides a mock function with given fields: publication, schema, table
+func (_m *PostgreSQLClient) IsTableInPublication(publication string, schema string, table string) (bool, error) {
+	ret := _m.Called(publication, schema, table)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
+		return rf(publication, schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
+		r0 = rf(publication, schema, table)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(publication, schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsTableReadOnly provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) IsTableReadOnly(schema string, table string) (bool, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (bool, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBoundedPartitions provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) ListBoundedPartitions(schema string, table string) ([]postgresql.PartitionResult, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 []postgresql.PartitionResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]postgresql.PartitionResult, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []postgresql.PartitionResult); ok {
+		r0 = rf(schema, table)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgresql.PartitionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCheckConstraints provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) ListCheckConstraints(schema string, table string) ([]string, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]string, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []string); ok {
+		r0 = rf(schema, table)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListColumns provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) ListColumns(schema string, table string) ([]postgresql.Column, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 []postgresql.Column
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]postgresql.Column, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []postgresql.Column); ok {
+		r0 = rf(schema, table)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgresql.Column)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListInvalidIndexes provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) ListInvalidIndexes(schema string, table string) ([]postgresql.PartitionIndexResult, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 []postgresql.PartitionIndexResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]postgresql.PartitionIndexResult, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []postgresql.PartitionIndexResult); ok {
+		r0 = rf(schema, table)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgresql.PartitionIndexResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPartitionIndexes provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) ListPartitionIndexes(schema string, table string) ([]postgresql.PartitionIndexResult, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 []postgresql.PartitionIndexResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]postgresql.PartitionIndexResult, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []postgresql.PartitionIndexResult); ok {
+		r0 = rf(schema, table)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgresql.PartitionIndexResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPartitionedIndexes provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) ListPartitionedIndexes(schema string, table string) ([]postgresql.PartitionedIndex, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 []postgresql.PartitionedIndex
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]postgresql.PartitionedIndex, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []postgresql.PartitionedIndex); ok {
+		r0 = rf(schema, table)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgresql.PartitionedIndex)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListPartitions provides a mock function with given fields: schema, table
 func (_m *PostgreSQLClient) ListPartitions(schema string, table string) ([]postgresql.PartitionResult, error) {
 	ret := _m.Called(schema, table)
@@ -275,6 +1028,212 @@ func (_m *PostgreSQLClient) ListPartitions(schema string, table string) ([]postg
 	return r0, r1
 }
 
+// ListTableIndexes provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) ListTableIndexes(schema string, table string) ([]postgresql.PartitionedIndex, error) {
+	ret := _m.Called(schema, table)
+
+	var r0 []postgresql.PartitionedIndex
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]postgresql.PartitionedIndex, error)); ok {
+		return rf(schema, table)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []postgresql.PartitionedIndex); ok {
+		r0 = rf(schema, table)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgresql.PartitionedIndex)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(schema, table)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTablesByComment provides a mock function with given fields: marker
+func (_m *PostgreSQLClient) ListTablesByComment(marker string) ([]postgresql.TableResult, error) {
+	ret := _m.Called(marker)
+
+	var r0 []postgresql.TableResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]postgresql.TableResult, error)); ok {
+		return rf(marker)
+	}
+	if rf, ok := ret.Get(0).(func(string) []postgresql.TableResult); ok {
+		r0 = rf(marker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postgresql.TableResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(marker)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReindexConcurrently provides a mock function with given fields: schema, index
+func (_m *PostgreSQLClient) ReindexConcurrently(schema string, index string) error {
+	ret := _m.Called(schema, index)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(schema, index)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenameIndex provides a mock function with given fields: schema, index, newName
+func (_m *PostgreSQLClient) RenameIndex(schema string, index string, newName string) error {
+	ret := _m.Called(schema, index, newName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(schema, index, newName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenameTable provides a mock function with given fields: schema, table, newName
+func (_m *PostgreSQLClient) RenameTable(schema string, table string, newName string) error {
+	ret := _m.Called(schema, table, newName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(schema, table, newName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenameTableAndIndexes provides a mock function with given fields: schema, table, newName, indexes
+func (_m *PostgreSQLClient) RenameTableAndIndexes(schema string, table string, newName string, indexes []postgresql.IndexRename) error {
+	ret := _m.Called(schema, table, newName, indexes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, []postgresql.IndexRename) error); ok {
+		r0 = rf(schema, table, newName, indexes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplacePartitions provides a mock function with given fields: schema, parent, partitions, attachments
+func (_m *PostgreSQLClient) ReplacePartitions(schema string, parent string, partitions []string, attachments []postgresql.Attachment) error {
+	ret := _m.Called(schema, parent, partitions, attachments)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, []string, []postgresql.Attachment) error); ok {
+		r0 = rf(schema, parent, partitions, attachments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPartitionReplicaIdentity provides a mock function with given fields: schema, table, parent
+func (_m *PostgreSQLClient) SetPartitionReplicaIdentity(schema string, table string, parent string) error {
+	ret := _m.Called(schema, table, parent)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(schema, table, parent)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTableComment provides a mock function with given fields: schema, table, comment
+func (_m *PostgreSQLClient) SetTableComment(schema string, table string, comment string) error {
+	ret := _m.Called(schema, table, comment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(schema, table, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTableReadOnly provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) SetTableReadOnly(schema string, table string) error {
+	ret := _m.Called(schema, table)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTableSchema provides a mock function with given fields: schema, table, newSchema
+func (_m *PostgreSQLClient) SetTableSchema(schema string, table string, newSchema string) error {
+	ret := _m.Called(schema, table, newSchema)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(schema, table, newSchema)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TruncateTable provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) TruncateTable(schema string, table string) error {
+	ret := _m.Called(schema, table)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnsetTableReadOnly provides a mock function with given fields: schema, table
+func (_m *PostgreSQLClient) UnsetTableReadOnly(schema string, table string) error {
+	ret := _m.Called(schema, table)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(schema, table)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewPostgreSQLClient creates a new instance of PostgreSQLClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPostgreSQLClient(t interface {
@@ -3,7 +3,9 @@ package ppm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 
 type PostgreSQLClient interface {
 	ListPartitions(schema, table string) (partitions []postgresql.PartitionResult, err error)
+	ListBoundedPartitions(schema, table string) (partitions []postgresql.PartitionResult, err error)
 	GetEngineVersion() (int64, error)
 	GetServerTime() (time.Time, error)
 	IsTableExists(schema, table string) (bool, error)
@@ -25,14 +28,64 @@ type PostgreSQLClient interface {
 	DetachPartitionConcurrently(schema, table, parent string) error
 	FinalizePartitionDetach(schema, table, parent string) error
 	SetPartitionReplicaIdentity(schema, table, parent string) error
+	SetTableReadOnly(schema, table string) error
+	UnsetTableReadOnly(schema, table string) error
+	IsTableReadOnly(schema, table string) (bool, error)
+	CreateIndexConcurrently(schema, table, index, definition string) error
+	DropIndexConcurrently(schema, index string) error
+	RenameIndex(schema, index, newName string) error
+	GetIndexStatus(schema, index string) (postgresql.IndexStatus, error)
+	CreateIndexOnParent(schema, table, index, definition string) error
+	AttachIndex(schema, parentIndex, index string) error
+	AddIndexConstraint(schema, table, index, constraint string) error
+	IsIndexPartitionAttached(schema, parentIndex, table string) (bool, error)
+	CreateUniqueIndexConcurrently(schema, table, index, definition string) error
+	ReindexConcurrently(schema, index string) error
+	ListPartitionedIndexes(schema, table string) ([]postgresql.PartitionedIndex, error)
+	ListPartitionIndexes(schema, table string) ([]postgresql.PartitionIndexResult, error)
+	ListInvalidIndexes(schema, table string) ([]postgresql.PartitionIndexResult, error)
+	AddTableToPublication(publication, schema, table string) error
+	DropTableFromPublication(publication, schema, table string) error
+	IsTableInPublication(publication, schema, table string) (bool, error)
+	CopyTableTo(schema, table, format string, w io.Writer) (int64, error)
+	GetColumnsDefinition(schema, table string) (string, error)
+	CountRows(schema, table string) (int64, error)
+	CopyTableFrom(schema, table, format string, r io.Reader) (int64, error)
+	CreateTableFromDefinition(definition string) error
+	GetTableComment(schema, table string) (string, error)
+	SetTableComment(schema, table, comment string) error
+	RenameTable(schema, table, newName string) error
+	RenameTableAndIndexes(schema, table, newName string, indexes []postgresql.IndexRename) error
+	SetTableSchema(schema, table, newSchema string) error
+	ListTablesByComment(marker string) ([]postgresql.TableResult, error)
+	GetTableSize(schema, table string) (int64, error)
+	GetRowEstimate(schema, table string) (int64, error)
+	HasRowsFrom(schema, table, column, value string) (bool, error)
+	TruncateTable(schema, table string) error
+	IsTableEmpty(schema, table string) (bool, error)
+	DeleteRowsBefore(schema, table, column, value string, limit int) (int64, error)
+	EvaluateQuery(query string, lowerBound, upperBound time.Time) (any, error)
+	GetDefaultPartition(schema, table string) (string, string, error)
+	AttachPartitionWithDefaultRows(schema, table, parent, defaultSchema, defaultTable, column, lowerBound, upperBound string) error
+	GetBatchUpperBound(schema, table, column, lowerBound, upperBound string, limit int) (string, error)
+	CopyRows(sourceSchema, sourceTable, targetSchema, targetTable, column, lowerBound, upperBound string) (int64, error)
+	AddBoundsConstraint(schema, table, constraint, column, lowerBound, upperBound string) error
+	DropConstraint(schema, table, constraint string) error
+	ReplacePartitions(schema, parent string, partitions []string, attachments []postgresql.Attachment) error
+	ListColumns(schema, table string) ([]postgresql.Column, error)
+	ListCheckConstraints(schema, table string) ([]string, error)
+	ListTableIndexes(schema, table string) ([]postgresql.PartitionedIndex, error)
 }
 
+var ErrUnknownPartitionConfiguration = errors.New("unknown partition configuration")
+
 type PPM struct {
 	ctx        context.Context
 	db         PostgreSQLClient
 	partitions map[string]partition.Configuration
 	logger     slog.Logger
 	workDate   time.Time
+	force      bool // override cleanup safeguards
 }
 
 func New(context context.Context, logger slog.Logger, db PostgreSQLClient, partitions map[string]partition.Configuration, workDate time.Time) *PPM {
@@ -45,6 +98,11 @@ func New(context context.Context, logger slog.Logger, db PostgreSQLClient, parti
 	}
 }
 
+// SetForce allows cleanup to remove partitions refused by safeguards
+func (p *PPM) SetForce(force bool) {
+	p.force = force
+}
+
 func getExpectedPartitions(partition partition.Configuration, currentTime time.Time) (partitions []partition.Partition, err error) {
 	retentions, err := partition.GetRetentionPartitions(currentTime)
 	if err != nil {
@@ -67,3 +125,19 @@ func getExpectedPartitions(partition partition.Configuration, currentTime time.T
 
 	return
 }
+
+// getConfiguration returns the partition configuration matching the name.
+// The name could be either the configuration key or the managed table name.
+func (p PPM) getConfiguration(name string) (partition.Configuration, error) {
+	if config, found := p.partitions[name]; found {
+		return config, nil
+	}
+
+	for _, config := range p.partitions {
+		if config.Table == name {
+			return config, nil
+		}
+	}
+
+	return partition.Configuration{}, fmt.Errorf("%w: %s", ErrUnknownPartitionConfiguration, name)
+}
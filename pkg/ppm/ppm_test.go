@@ -19,6 +19,7 @@ func partitionResultToPartition(t *testing.T, partitions []partition.Partition)
 			Name:        p.Name,
 			LowerBound:  p.LowerBound.Format(boundDateFormat),
 			UpperBound:  p.UpperBound.Format(boundDateFormat),
+			Comment:     p.Comment,
 		})
 	}
 
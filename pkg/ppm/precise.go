@@ -0,0 +1,144 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/expression"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+// enforcePreciseRetention deletes the rows older than the exact retention from the oldest retained partition.
+// Retention is otherwise enforced per partition, which keeps up to one interval of extra rows.
+// The cutoff is computed from the server time, and the partition goes through the retention gate and the safeguards like a removal.
+func (p PPM) enforcePreciseRetention(config partition.Configuration, keepIf *expression.Expression) error {
+	serverTime, err := p.db.GetServerTime()
+	if err != nil {
+		return fmt.Errorf("could not get server time: %w", err)
+	}
+
+	cutoffDate, _, err := config.PreciseRetentionCutoff(serverTime)
+	if err != nil {
+		return err
+	}
+
+	foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	// Partitions entirely before the cutoff are left to cleanup, which did not remove them on purpose
+	var trimmed []partition.Partition
+
+	for _, part := range foundPartitions {
+		if !part.LowerBound.Before(cutoffDate) || !part.UpperBound.After(cutoffDate) {
+			continue
+		}
+
+		if kept, reason := p.isKept(config, keepIf, part); kept {
+			p.logger.Info("Partition is kept, skip precise retention", "schema", part.Schema, "table", part.Name, "reason", reason)
+
+			continue
+		}
+
+		trimmed = append(trimmed, part)
+	}
+
+	trimmed, err = p.applyRetentionGate(config, trimmed)
+	if err != nil {
+		return err
+	}
+
+	trimmed, refusedErr := p.applySafeguards(config, foundPartitions, trimmed, true)
+	if refusedErr != nil && !errors.Is(refusedErr, ErrCleanupRefused) {
+		return refusedErr
+	}
+
+	if len(trimmed) == 0 {
+		return refusedErr
+	}
+
+	_, partitionKey, err := p.db.GetPartitionSettings(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("failed to get partition settings: %w", err)
+	}
+
+	keyType, err := p.db.GetColumnDataType(config.Schema, config.Table, partitionKey)
+	if err != nil {
+		return fmt.Errorf("failed to get partition key details: %w", err)
+	}
+
+	cutoff, err := formatBound(keyType, cutoffDate)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range trimmed {
+		err = p.deleteExpiredRows(config, part, partitionKey, cutoff)
+		if err != nil {
+			return fmt.Errorf("failed to delete expired rows of %s: %w", part.Name, err)
+		}
+	}
+
+	return refusedErr
+}
+
+// deleteExpiredRows deletes rows before the cutoff from the partition.
+// The read-only protection of the partition, which rejects DELETE, is removed for the deletion and set again afterwards.
+func (p PPM) deleteExpiredRows(config partition.Configuration, part partition.Partition, partitionKey, cutoff string) error {
+	if config.ReadOnlyAfter > 0 {
+		readOnly, err := p.db.IsTableReadOnly(part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check read-only status: %w", err)
+		}
+
+		if readOnly {
+			err = p.db.UnsetTableReadOnly(part.Schema, part.Name)
+			if err != nil {
+				return fmt.Errorf("failed to unset partition read-only: %w", err)
+			}
+
+			err = p.deleteRowsInBatches(config, part, partitionKey, cutoff)
+
+			protectErr := p.db.SetTableReadOnly(part.Schema, part.Name)
+			if protectErr != nil {
+				return errors.Join(err, fmt.Errorf("failed to set partition read-only again: %w", protectErr))
+			}
+
+			return err
+		}
+	}
+
+	return p.deleteRowsInBatches(config, part, partitionKey, cutoff)
+}
+
+// deleteRowsInBatches deletes rows before the cutoff in batches, pausing between batches to limit the load
+func (p PPM) deleteRowsInBatches(config partition.Configuration, part partition.Partition, partitionKey, cutoff string) error {
+	batchSize := config.PreciseRetention.GetBatchSize()
+
+	var total int64
+
+	for {
+		deleted, err := p.db.DeleteRowsBefore(part.Schema, part.Name, partitionKey, cutoff, batchSize)
+		if err != nil {
+			return err
+		}
+
+		total += deleted
+
+		if deleted < int64(batchSize) {
+			break
+		}
+
+		p.logger.Info("Deleting expired rows", "schema", part.Schema, "table", part.Name, "cutoff", cutoff, "deleted", total)
+
+		time.Sleep(config.PreciseRetention.GetBatchPause())
+	}
+
+	if total > 0 {
+		p.logger.Info("Expired rows deleted", "schema", part.Schema, "table", part.Name, "cutoff", cutoff, "deleted", total)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupEnforcesPreciseRetention(t *testing.T) {
+	serverTime := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	wrongWorkDate := serverTime.AddDate(0, 0, 5)
+
+	config := OneDayPartitionConfiguration
+	config.Interval = partition.Monthly
+	config.Retention = 12
+	config.PreciseRetention = &partition.PreciseRetentionConfiguration{Days: 365, BatchSize: 2, BatchPause: 1}
+
+	var existing []partition.Partition
+
+	for i := -12; i <= 1; i++ {
+		part, _ := config.GeneratePartition(serverTime.AddDate(0, i, 0))
+		existing = append(existing, part)
+	}
+
+	boundary := existing[0]
+
+	testCases := []struct {
+		name     string
+		readOnly bool
+		gate     bool
+		deleted  bool
+	}{
+		{"Delete expired rows", false, false, true},
+		{"Delete expired rows of a read-only partition", true, false, true},
+		{"Wait for the retention gate", false, true, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := config
+			if tc.readOnly {
+				config.ReadOnlyAfter = 1
+			}
+
+			if tc.gate {
+				config.RetentionGate = "SELECT false"
+			}
+
+			checker, postgreSQLMock := setupPPM(t, config, wrongWorkDate)
+
+			// The cutoff is computed from the server time, not from the work date
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil)
+			postgreSQLMock.On("GetServerTime").Return(serverTime, nil).Once()
+
+			if tc.gate {
+				postgreSQLMock.On("EvaluateQuery", config.RetentionGate, boundary.LowerBound, boundary.UpperBound).Return(false, nil).Once()
+			} else {
+				postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+				postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+			}
+
+			// The read-only protection is removed for the deletion, and set again
+			if tc.readOnly {
+				postgreSQLMock.On("IsTableReadOnly", boundary.Schema, boundary.Name).Return(true, nil).Once()
+				postgreSQLMock.On("UnsetTableReadOnly", boundary.Schema, boundary.Name).Return(nil).Once()
+				postgreSQLMock.On("SetTableReadOnly", boundary.Schema, boundary.Name).Return(nil).Once()
+			}
+
+			// Only the oldest partition contains rows older than 365 days
+			if tc.deleted {
+				postgreSQLMock.On("DeleteRowsBefore", boundary.Schema, boundary.Name, config.PartitionKey, "2023-06-16", 2).Return(int64(2), nil).Once()
+				postgreSQLMock.On("DeleteRowsBefore", boundary.Schema, boundary.Name, config.PartitionKey, "2023-06-16", 2).Return(int64(1), nil).Once()
+			}
+
+			assert.Nil(t, checker.CleanupPartitions(), "CleanupPartitions should succeed")
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPreciseRetentionFailure(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	config.Interval = partition.Monthly
+	config.Retention = 12
+	config.PreciseRetention = &partition.PreciseRetentionConfiguration{Days: 365}
+
+	var existing []partition.Partition
+
+	for i := -12; i <= 1; i++ {
+		part, _ := config.GeneratePartition(workDate.AddDate(0, i, 0))
+		existing = append(existing, part)
+	}
+
+	boundary := existing[0]
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Twice()
+	postgreSQLMock.On("GetServerTime").Return(workDate, nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("DeleteRowsBefore", boundary.Schema, boundary.Name, config.PartitionKey, "2023-06-16", 10000).Return(int64(0), ErrFake).Once()
+
+	assert.ErrorIs(t, checker.CleanupPartitions(), ppm.ErrPartitionCleanupFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
@@ -3,12 +3,11 @@ package ppm
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
-	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
 	"github.com/qonto/postgresql-partition-manager/internal/infra/retry"
-	"github.com/qonto/postgresql-partition-manager/internal/infra/uuid7"
 )
 
 var ErrPartitionProvisioningFailed = errors.New("partition provisioning failed for one or more partition")
@@ -46,18 +45,34 @@ func (p PPM) provisionPartitionsFor(config partition.Configuration, at time.Time
 		return fmt.Errorf("could not generate partition to create: %w", err)
 	}
 
-	currentRange, err := p.getGlobalRange(foundPartitions)
+	partitions, err = p.withoutBudgetedPartitions(config, partitions)
 	if err != nil {
-		return fmt.Errorf("could not evaluate existing ranges: %w", err)
+		return fmt.Errorf("could not evaluate partitions removed by the size budget: %w", err)
 	}
 
-	p.logger.Info("Current ", "c_range", currentRange.String())
-
 	expectedRange, err := p.getGlobalRange(partitions)
 	if err != nil {
 		return fmt.Errorf("could not evaluate ranges to create: %w", err)
 	}
 
+	// Expired partitions of recycle tables take the place of the partitions to create
+	foundPartitions, err = p.recycleExpiredPartitions(config, partitions, foundPartitions)
+	if err != nil {
+		return fmt.Errorf("could not recycle expired partitions: %w", err)
+	}
+
+	foundPartitions = p.withoutKeptPartitions(config, nil, foundPartitions, expectedRange)
+
+	if config.Sparse {
+		return p.provisionSparsePartitions(config, partitions, foundPartitions)
+	}
+
+	currentRange, err := p.getGlobalRange(foundPartitions)
+	if err != nil {
+		return fmt.Errorf("could not evaluate existing ranges: %w", err)
+	}
+
+	p.logger.Info("Current ", "c_range", currentRange.String())
 	p.logger.Info("Expected", "e_range", expectedRange)
 
 	if expectedRange.IsEqual(currentRange) {
@@ -79,7 +94,7 @@ func (p PPM) provisionPartitionsFor(config partition.Configuration, at time.Time
 			// left segment of the candidate outside, of the intersection with existing partitions
 			segLeft := candidate
 			segLeft.UpperBound = currentRange.LowerBound
-			segLeft.Name = fmt.Sprintf("%s_%s_%s", config.Table, segLeft.LowerBound.Format("20060102"), segLeft.UpperBound.Format("20060102"))
+			segLeft.Name = config.SegmentName(segLeft.LowerBound, segLeft.UpperBound)
 			p.logger.Info("Left intersection", "create-range", partition.Bounds(segLeft.LowerBound, segLeft.UpperBound))
 			err = p.CreatePartition(config, segLeft)
 		}
@@ -88,7 +103,7 @@ func (p PPM) provisionPartitionsFor(config partition.Configuration, at time.Time
 			// right segment of the candidate, outside of the intersection with existing partitions
 			segRight := candidate
 			segRight.LowerBound = currentRange.UpperBound
-			segRight.Name = fmt.Sprintf("%s_%s_%s", config.Table, segRight.LowerBound.Format("20060102"), segRight.UpperBound.Format("20060102"))
+			segRight.Name = config.SegmentName(segRight.LowerBound, segRight.UpperBound)
 			p.logger.Info("Right intersection", "create-range", partition.Bounds(segRight.LowerBound, segRight.UpperBound))
 			err = p.CreatePartition(config, segRight)
 		}
@@ -103,9 +118,60 @@ func (p PPM) provisionPartitionsFor(config partition.Configuration, at time.Time
 	return nil
 }
 
+// provisionSparsePartitions creates the expected partitions after the newest existing partition.
+// Existing partitions are left as they are, and gaps between them are not filled, since they may have been dropped on purpose.
+func (p PPM) provisionSparsePartitions(config partition.Configuration, expected, foundPartitions []partition.Partition) error {
+	newest := newestUpperBound(foundPartitions)
+
+	for _, candidate := range expected {
+		overlapping := slices.IndexFunc(foundPartitions, candidate.Overlaps)
+		if overlapping >= 0 {
+			existing := foundPartitions[overlapping]
+			if !existing.LowerBound.Equal(candidate.LowerBound) || !existing.UpperBound.Equal(candidate.UpperBound) {
+				p.logger.Warn("Expected partition overlaps an existing partition, skip", "schema", candidate.Schema, "table", candidate.Name, "existing", existing.Name)
+			}
+
+			continue
+		}
+
+		if candidate.LowerBound.Before(newest) {
+			p.logger.Debug("Expected partition is in a gap between existing partitions, skip", "schema", candidate.Schema, "table", candidate.Name)
+
+			continue
+		}
+
+		p.logger.Info("No intersection", "create-range", partition.Bounds(candidate.LowerBound, candidate.UpperBound))
+
+		err := p.CreatePartition(config, candidate)
+		if err != nil {
+			p.logger.Error("Failed to create partition", "error", err)
+
+			return ErrPartitionProvisioningFailed
+		}
+	}
+
+	return nil
+}
+
+// newestUpperBound returns the upper bound of the newest partition, or the zero time when there is no partition
+func newestUpperBound(partitions []partition.Partition) (newest time.Time) {
+	for _, part := range partitions {
+		if part.UpperBound.After(newest) {
+			newest = part.UpperBound
+		}
+	}
+
+	return newest
+}
+
 func (p PPM) CreatePartition(partitionConfiguration partition.Configuration, partition partition.Partition) error {
 	p.logger.Debug("Creating partition", "schema", partition.Schema, "table", partition.Name)
 
+	err := checkIdentifier(partition.Name)
+	if err != nil {
+		return err
+	}
+
 	_, partitionKey, err := p.db.GetPartitionSettings(partition.Schema, partition.ParentTable)
 	if err != nil {
 		return fmt.Errorf("failed to get partition settings: %w", err)
@@ -140,23 +206,17 @@ func (p PPM) CreatePartition(partitionConfiguration partition.Configuration, par
 	if partitionAttached {
 		p.logger.Info("Table is already attached to the parent table, skip", "schema", partition.Schema, "table", partition.Name)
 
-		return nil
+		return p.addToPublications(partitionConfiguration, partition)
 	}
 
-	var lowerBound, upperBound string
+	lowerBound, err := formatBound(partitionKeyType, partition.LowerBound)
+	if err != nil {
+		return err
+	}
 
-	switch partitionKeyType {
-	case postgresql.Date:
-		lowerBound = partition.LowerBound.Format("2006-01-02")
-		upperBound = partition.UpperBound.Format("2006-01-02")
-	case postgresql.DateTime, postgresql.DateTimeWithTZ:
-		lowerBound = partition.LowerBound.Format("2006-01-02 00:00:00")
-		upperBound = partition.UpperBound.Format("2006-01-02 00:00:00")
-	case postgresql.UUID:
-		lowerBound = uuid7.FromTime(partition.LowerBound)
-		upperBound = uuid7.FromTime(partition.UpperBound)
-	default:
-		return ErrUnsupportedPartitionStrategy
+	upperBound, err := formatBound(partitionKeyType, partition.UpperBound)
+	if err != nil {
+		return err
 	}
 
 	maxRetries := 3
@@ -184,5 +244,5 @@ func (p PPM) CreatePartition(partitionConfiguration partition.Configuration, par
 
 	p.logger.Info("Partition attached to parent table", "schema", partition.Schema, "table", partition.Name, "parent_table", partition.ParentTable)
 
-	return nil
+	return p.addToPublications(partitionConfiguration, partition)
 }
@@ -0,0 +1,86 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var ErrMissingPublicationMembership = errors.New("partitions are missing from publications")
+
+// addToPublications adds the partition to the configured publications.
+// Publications without publish_via_partition_root only replicate partitions explicitly added to them.
+func (p PPM) addToPublications(config partition.Configuration, part partition.Partition) error {
+	for _, publication := range config.Publications {
+		member, err := p.db.IsTableInPublication(publication, part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check publication membership: %w", err)
+		}
+
+		if member {
+			continue
+		}
+
+		err = p.db.AddTableToPublication(publication, part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to add partition to publication %s: %w", publication, err)
+		}
+
+		p.logger.Info("Partition added to publication", "schema", part.Schema, "table", part.Name, "publication", publication)
+	}
+
+	return nil
+}
+
+func (p PPM) removeFromPublications(config partition.Configuration, part partition.Partition) error {
+	for _, publication := range config.Publications {
+		member, err := p.db.IsTableInPublication(publication, part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check publication membership: %w", err)
+		}
+
+		if !member {
+			continue
+		}
+
+		err = p.db.DropTableFromPublication(publication, part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to remove partition from publication %s: %w", publication, err)
+		}
+
+		p.logger.Info("Partition removed from publication", "schema", part.Schema, "table", part.Name, "publication", publication)
+	}
+
+	return nil
+}
+
+func (p *PPM) checkPublications(config partition.Configuration) error {
+	foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	missing := make(map[string][]string)
+
+	for _, publication := range config.Publications {
+		for _, part := range foundPartitions {
+			member, err := p.db.IsTableInPublication(publication, part.Schema, part.Name)
+			if err != nil {
+				return fmt.Errorf("failed to check publication membership: %w", err)
+			}
+
+			if !member {
+				missing[publication] = append(missing[publication], part.Name)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		p.logger.Warn("Found partitions missing from publications", "publications", missing)
+
+		return ErrMissingPublicationMembership
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePartitionAddsToPublications(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Publications = []string{"cdc", "analytics"}
+	part, _ := config.GeneratePartition(tomorrow)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("GetPartitionSettings", part.Schema, part.ParentTable).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", part.Schema, part.ParentTable, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("IsTableExists", part.Schema, part.Name).Return(false, nil).Once()
+	postgreSQLMock.On("CreateTableLikeTable", part.Schema, part.Name, part.ParentTable).Return(nil).Once()
+	postgreSQLMock.On("IsPartitionAttached", part.Schema, part.Name).Return(false, nil).Once()
+	postgreSQLMock.On("AttachPartition", part.Schema, part.Name, part.ParentTable, part.LowerBound.Format("2006-01-02"), part.UpperBound.Format("2006-01-02")).Return(nil).Once()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", part.Schema, part.Name, part.ParentTable).Return(nil).Once()
+
+	// Already member of the first publication
+	postgreSQLMock.On("IsTableInPublication", "cdc", part.Schema, part.Name).Return(true, nil).Once()
+	postgreSQLMock.On("IsTableInPublication", "analytics", part.Schema, part.Name).Return(false, nil).Once()
+	postgreSQLMock.On("AddTableToPublication", "analytics", part.Schema, part.Name).Return(nil).Once()
+
+	err := checker.CreatePartition(config, part)
+
+	assert.Nil(t, err, "CreatePartition should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestCleanupRemovesFromPublications(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Publications = []string{"cdc", "analytics"}
+
+	dayBeforeYesterdayPartition, _ := config.GeneratePartition(dayBeforeYesterday)
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+	existing := []partition.Partition{dayBeforeYesterdayPartition, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("DetachPartitionConcurrently", dayBeforeYesterdayPartition.Schema, dayBeforeYesterdayPartition.Name, dayBeforeYesterdayPartition.ParentTable).Return(nil).Once()
+	postgreSQLMock.On("IsTableInPublication", "cdc", dayBeforeYesterdayPartition.Schema, dayBeforeYesterdayPartition.Name).Return(true, nil).Once()
+	postgreSQLMock.On("DropTableFromPublication", "cdc", dayBeforeYesterdayPartition.Schema, dayBeforeYesterdayPartition.Name).Return(nil).Once()
+	postgreSQLMock.On("IsTableInPublication", "analytics", dayBeforeYesterdayPartition.Schema, dayBeforeYesterdayPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("DropTable", dayBeforeYesterdayPartition.Schema, dayBeforeYesterdayPartition.Name).Return(nil).Once()
+
+	err := checker.CleanupPartitions()
+
+	assert.Nil(t, err, "CleanupPartitions should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestCheckPublications(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Publications = []string{"cdc", "analytics"}
+
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+	existing := partitionResultToPartition(t, []partition.Partition{yesterdayPartition, currentPartition, tomorrowPartition})
+
+	testCases := []struct {
+		name    string
+		missing string
+		success bool
+	}{
+		{"All partitions are published", "", true},
+		{"Partition missing from a publication", tomorrowPartition.Name, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+			postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+			postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(existing, nil)
+
+			for _, publication := range config.Publications {
+				for _, part := range []partition.Partition{yesterdayPartition, currentPartition, tomorrowPartition} {
+					postgreSQLMock.On("IsTableInPublication", publication, part.Schema, part.Name).Return(part.Name != tc.missing, nil).Once()
+				}
+			}
+
+			err := checker.CheckPartitions()
+
+			if tc.success {
+				assert.Nil(t, err, "CheckPartitions should succeed")
+			} else {
+				assert.ErrorIs(t, err, ppm.ErrInvalidPartitionConfiguration)
+			}
+
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
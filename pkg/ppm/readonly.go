@@ -0,0 +1,154 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var (
+	ErrPartitionProtectionFailed = errors.New("at least one partition could not be protected")
+	ErrUnprotectedPartitions     = errors.New("closed partitions are not read-only")
+	ErrPartitionNotFound         = errors.New("partition not found")
+)
+
+// ProtectPartitions makes closed partitions read-only for partition sets having a readOnlyAfter setting
+func (p PPM) ProtectPartitions() error {
+	protectionFailed := false
+
+	for name, config := range p.partitions {
+		if config.ReadOnlyAfter == 0 {
+			continue
+		}
+
+		p.logger.Info("Protecting partition", "partition", name)
+
+		if err := p.protectPartitionsFor(config); err != nil {
+			p.logger.Error("Failed to protect partitions", "error", err, "schema", config.Schema, "table", config.Table)
+
+			protectionFailed = true
+		}
+	}
+
+	if protectionFailed {
+		return ErrPartitionProtectionFailed
+	}
+
+	p.logger.Info("All closed partitions are protected")
+
+	return nil
+}
+
+func (p PPM) protectPartitionsFor(config partition.Configuration) error {
+	closedPartitions, err := p.getClosedPartitions(config)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range closedPartitions {
+		readOnly, err := p.db.IsTableReadOnly(part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check read-only status: %w", err)
+		}
+
+		if readOnly {
+			p.logger.Debug("Partition is already read-only, skip", "schema", part.Schema, "table", part.Name)
+
+			continue
+		}
+
+		err = p.db.SetTableReadOnly(part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to set partition read-only: %w", err)
+		}
+
+		p.logger.Info("Partition is now read-only", "schema", part.Schema, "table", part.Name, "parent_table", part.ParentTable)
+	}
+
+	return nil
+}
+
+// getClosedPartitions returns existing partitions older than the readOnlyAfter setting
+func (p PPM) getClosedPartitions(config partition.Configuration) (closedPartitions []partition.Partition, err error) {
+	boundary, err := config.AgeBoundary(p.workDate, config.ReadOnlyAfter)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute read-only boundary: %w", err)
+	}
+
+	foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return nil, fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	for _, part := range foundPartitions {
+		if !part.UpperBound.After(boundary) {
+			closedPartitions = append(closedPartitions, part)
+		}
+	}
+
+	return closedPartitions, nil
+}
+
+func (p *PPM) checkReadOnlyPartitions(config partition.Configuration) error {
+	closedPartitions, err := p.getClosedPartitions(config)
+	if err != nil {
+		return err
+	}
+
+	var unprotected []partition.Partition
+
+	for _, part := range closedPartitions {
+		readOnly, err := p.db.IsTableReadOnly(part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check read-only status: %w", err)
+		}
+
+		if !readOnly {
+			unprotected = append(unprotected, part)
+		}
+	}
+
+	if len(unprotected) > 0 {
+		p.logger.Warn("Found closed partitions that are not read-only", "tables", unprotected)
+
+		return ErrUnprotectedPartitions
+	}
+
+	return nil
+}
+
+// UnlockPartition removes the read-only protection of a partition to allow sanctioned corrections.
+// The protection is restored by the next protect operation.
+func (p PPM) UnlockPartition(name, partitionName string) error {
+	config, err := p.getConfiguration(name)
+	if err != nil {
+		return err
+	}
+
+	foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	for _, part := range foundPartitions {
+		if part.Name != partitionName {
+			continue
+		}
+
+		err = p.db.UnsetTableReadOnly(part.Schema, part.Name)
+		if err != nil {
+			p.logger.Error("Failed to unlock partition", "error", err, "schema", part.Schema, "table", part.Name)
+
+			return fmt.Errorf("failed to unlock partition: %w", err)
+		}
+
+		p.logger.Warn("Partition unlocked, it will be read-only again on the next protect operation", "schema", part.Schema, "table", part.Name, "parent_table", part.ParentTable)
+
+		return nil
+	}
+
+	p.logger.Error("Partition not found", "schema", config.Schema, "table", partitionName, "parent_table", config.Table)
+
+	return fmt.Errorf("%w: %s", ErrPartitionNotFound, partitionName)
+}
@@ -0,0 +1,122 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectPartitions(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.ReadOnlyAfter = 1
+
+	dayBeforeYesterdayPartition, _ := config.GeneratePartition(dayBeforeYesterday)
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	existing := []partition.Partition{dayBeforeYesterdayPartition, yesterdayPartition, currentPartition, tomorrowPartition}
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	// Already protected partition is skipped
+	postgreSQLMock.On("IsTableReadOnly", dayBeforeYesterdayPartition.Schema, dayBeforeYesterdayPartition.Name).Return(true, nil).Once()
+
+	postgreSQLMock.On("IsTableReadOnly", yesterdayPartition.Schema, yesterdayPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("SetTableReadOnly", yesterdayPartition.Schema, yesterdayPartition.Name).Return(nil).Once()
+
+	err := checker.ProtectPartitions()
+
+	assert.Nil(t, err, "ProtectPartitions should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestProtectPartitionsFailure(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.ReadOnlyAfter = 1
+
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	existing := []partition.Partition{yesterdayPartition, currentPartition}
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("IsTableReadOnly", yesterdayPartition.Schema, yesterdayPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("SetTableReadOnly", yesterdayPartition.Schema, yesterdayPartition.Name).Return(ErrFake).Once()
+
+	err := checker.ProtectPartitions()
+
+	assert.ErrorIs(t, err, ppm.ErrPartitionProtectionFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestCheckUnprotectedPartitions(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.ReadOnlyAfter = 1
+
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+	existing := partitionResultToPartition(t, []partition.Partition{yesterdayPartition, currentPartition, tomorrowPartition})
+
+	testCases := []struct {
+		name      string
+		protected bool
+		success   bool
+	}{
+		{"Closed partition is read-only", true, true},
+		{"Closed partition is writable", false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+			postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+			postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(existing, nil).Twice()
+			postgreSQLMock.On("IsTableReadOnly", yesterdayPartition.Schema, yesterdayPartition.Name).Return(tc.protected, nil).Once()
+
+			err := checker.CheckPartitions()
+
+			if tc.success {
+				assert.Nil(t, err, "CheckPartitions should succeed")
+			} else {
+				assert.ErrorIs(t, err, ppm.ErrInvalidPartitionConfiguration)
+			}
+
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUnlockPartition(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.ReadOnlyAfter = 1
+
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	existing := partitionResultToPartition(t, []partition.Partition{yesterdayPartition, currentPartition})
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(existing, nil)
+	postgreSQLMock.On("UnsetTableReadOnly", yesterdayPartition.Schema, yesterdayPartition.Name).Return(nil).Once()
+
+	// Lookup by configuration name
+	assert.Nil(t, checker.UnlockPartition("unittest", yesterdayPartition.Name), "UnlockPartition should succeed")
+
+	// Unknown partition
+	assert.ErrorIs(t, checker.UnlockPartition(config.Table, "unknown"), ppm.ErrPartitionNotFound)
+
+	// Unknown configuration
+	assert.ErrorIs(t, checker.UnlockPartition("unknown", yesterdayPartition.Name), ppm.ErrUnknownPartitionConfiguration)
+
+	postgreSQLMock.AssertExpectations(t)
+}
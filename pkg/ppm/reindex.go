@@ -0,0 +1,132 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+)
+
+var (
+	ErrInvalidOrMissingIndexes = errors.New("invalid or missing partition indexes")
+	ErrIndexRepairFailed       = errors.New("at least one partition index could not be repaired")
+)
+
+// checkPartitionIndexes reports partitions missing an index of the partitioned table, and invalid partition indexes.
+// Both keep the partitioned index invalid, which silently degrades query plans.
+func (p *PPM) checkPartitionIndexes(config partition.Configuration) error {
+	partitionIndexes, err := p.db.ListPartitionIndexes(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partition indexes: %w", err)
+	}
+
+	var missing []postgresql.PartitionIndexResult
+
+	for _, index := range partitionIndexes {
+		if !index.Attached {
+			missing = append(missing, index)
+		}
+	}
+
+	invalid, err := p.db.ListInvalidIndexes(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list invalid indexes: %w", err)
+	}
+
+	if len(missing) > 0 {
+		p.logger.Warn("Found partitions missing an index of the parent table", "indexes", missing)
+	}
+
+	if len(invalid) > 0 {
+		p.logger.Warn("Found invalid partition indexes", "indexes", invalid)
+	}
+
+	if len(missing) > 0 || len(invalid) > 0 {
+		return ErrInvalidOrMissingIndexes
+	}
+
+	return nil
+}
+
+// RepairIndexes rebuilds invalid partition indexes and creates the missing ones concurrently, then attaches them to the parent index
+func (p PPM) RepairIndexes() error {
+	repairFailed := false
+
+	for name, config := range p.partitions {
+		p.logger.Info("Repairing partition indexes", "partition", name)
+
+		if err := p.repairIndexesFor(config); err != nil {
+			p.logger.Error("Failed to repair partition indexes", "error", err, "schema", config.Schema, "table", config.Table)
+
+			repairFailed = true
+		}
+	}
+
+	if repairFailed {
+		return ErrIndexRepairFailed
+	}
+
+	p.logger.Info("All partition indexes are valid")
+
+	return nil
+}
+
+func (p PPM) repairIndexesFor(config partition.Configuration) error {
+	invalidIndexes, err := p.db.ListInvalidIndexes(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list invalid indexes: %w", err)
+	}
+
+	for _, index := range invalidIndexes {
+		if index.Attached {
+			// Attached indexes can't be dropped without the parent index
+			err = p.db.ReindexConcurrently(config.Schema, index.Index)
+		} else {
+			// Unattached invalid indexes are leftovers of interrupted builds
+			err = p.db.DropIndexConcurrently(config.Schema, index.Index)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to repair invalid index %s: %w", index.Index, err)
+		}
+
+		p.logger.Info("Invalid index repaired", "schema", config.Schema, "table", index.Partition, "index", index.Index, "attached", index.Attached)
+	}
+
+	parentIndexes, err := p.db.ListPartitionedIndexes(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitioned indexes: %w", err)
+	}
+
+	definitions := make(map[string]postgresql.PartitionedIndex, len(parentIndexes))
+	for _, index := range parentIndexes {
+		definitions[index.Name] = index
+	}
+
+	partitionIndexes, err := p.db.ListPartitionIndexes(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partition indexes: %w", err)
+	}
+
+	for _, index := range partitionIndexes {
+		if index.Attached {
+			continue
+		}
+
+		part := partition.Partition{
+			Schema:      config.Schema,
+			Name:        index.Partition,
+			ParentTable: config.Table,
+		}
+
+		err = p.rolloutPartitionIndex(part, definitions[index.ParentIndex])
+		if err != nil {
+			return fmt.Errorf("failed to create missing index of %s: %w", index.ParentIndex, err)
+		}
+
+		p.logger.Info("Missing index created and attached", "schema", config.Schema, "table", index.Partition, "parent_index", index.ParentIndex)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPartitionIndexes(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.CheckIndexes = true
+
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+	existing := partitionResultToPartition(t, []partition.Partition{yesterdayPartition, currentPartition, tomorrowPartition})
+
+	attached := postgresql.PartitionIndexResult{ParentIndex: "my_table_idx", Partition: currentPartition.Name, Index: "idx", Valid: true, Attached: true}
+	missing := postgresql.PartitionIndexResult{ParentIndex: "my_table_idx", Partition: tomorrowPartition.Name}
+	invalid := postgresql.PartitionIndexResult{Partition: yesterdayPartition.Name, Index: "leftover"}
+
+	testCases := []struct {
+		name             string
+		partitionIndexes []postgresql.PartitionIndexResult
+		invalidIndexes   []postgresql.PartitionIndexResult
+		success          bool
+	}{
+		{"All partition indexes are valid", []postgresql.PartitionIndexResult{attached}, nil, true},
+		{"Missing partition index", []postgresql.PartitionIndexResult{attached, missing}, nil, false},
+		{"Invalid partition index", []postgresql.PartitionIndexResult{attached}, []postgresql.PartitionIndexResult{invalid}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+			postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+			postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(existing, nil).Once()
+			postgreSQLMock.On("ListPartitionIndexes", config.Schema, config.Table).Return(tc.partitionIndexes, nil).Once()
+			postgreSQLMock.On("ListInvalidIndexes", config.Schema, config.Table).Return(tc.invalidIndexes, nil).Once()
+
+			err := checker.CheckPartitions()
+
+			if tc.success {
+				assert.Nil(t, err, "CheckPartitions should succeed")
+			} else {
+				assert.ErrorIs(t, err, ppm.ErrInvalidPartitionConfiguration)
+			}
+
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepairIndexes(t *testing.T) {
+	config := OneDayPartitionConfiguration
+
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+
+	pkey := postgresql.PartitionedIndex{Name: "my_table_pkey", Definition: "USING btree (id, created_at)", Unique: true, Constraint: "PRIMARY KEY"}
+	status := postgresql.PartitionedIndex{Name: "my_table_status", Definition: "USING btree (status)"}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListInvalidIndexes", config.Schema, config.Table).Return([]postgresql.PartitionIndexResult{
+		{ParentIndex: status.Name, Partition: currentPartition.Name, Index: "attached_invalid", Attached: true},
+		{Partition: currentPartition.Name, Index: "leftover"},
+	}, nil).Once()
+	postgreSQLMock.On("ReindexConcurrently", config.Schema, "attached_invalid").Return(nil).Once()
+	postgreSQLMock.On("DropIndexConcurrently", config.Schema, "leftover").Return(nil).Once()
+
+	postgreSQLMock.On("ListPartitionedIndexes", config.Schema, config.Table).Return([]postgresql.PartitionedIndex{pkey, status}, nil).Once()
+	postgreSQLMock.On("ListPartitionIndexes", config.Schema, config.Table).Return([]postgresql.PartitionIndexResult{
+		{ParentIndex: pkey.Name, Partition: currentPartition.Name, Index: "pkey", Valid: true, Attached: true},
+		{ParentIndex: pkey.Name, Partition: tomorrowPartition.Name},
+		{ParentIndex: status.Name, Partition: currentPartition.Name, Index: "attached_invalid", Attached: true},
+		{ParentIndex: status.Name, Partition: tomorrowPartition.Name},
+	}, nil).Once()
+
+	// Missing primary key index, the primary key constraint is added before the index is attached
+	pkeyIndex := partition.PartitionIndexName(tomorrowPartition, pkey.Name)
+	postgreSQLMock.On("IsIndexPartitionAttached", config.Schema, pkey.Name, tomorrowPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, pkeyIndex).Return(postgresql.IndexStatus{}, nil).Once()
+	postgreSQLMock.On("CreateUniqueIndexConcurrently", config.Schema, tomorrowPartition.Name, pkeyIndex, pkey.Definition).Return(nil).Once()
+	postgreSQLMock.On("AddIndexConstraint", config.Schema, tomorrowPartition.Name, pkeyIndex, "PRIMARY KEY").Return(nil).Once()
+	postgreSQLMock.On("AttachIndex", config.Schema, pkey.Name, pkeyIndex).Return(nil).Once()
+
+	// Missing index
+	statusIndex := partition.PartitionIndexName(tomorrowPartition, status.Name)
+	postgreSQLMock.On("IsIndexPartitionAttached", config.Schema, status.Name, tomorrowPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, statusIndex).Return(postgresql.IndexStatus{}, nil).Once()
+	postgreSQLMock.On("CreateIndexConcurrently", config.Schema, tomorrowPartition.Name, statusIndex, status.Definition).Return(nil).Once()
+	postgreSQLMock.On("AttachIndex", config.Schema, status.Name, statusIndex).Return(nil).Once()
+
+	err := checker.RepairIndexes()
+
+	assert.Nil(t, err, "RepairIndexes should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestRepairIndexesFailure(t *testing.T) {
+	config := OneDayPartitionConfiguration
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListInvalidIndexes", config.Schema, config.Table).Return([]postgresql.PartitionIndexResult{
+		{Partition: "my_table_2024_01_01", Index: "leftover"},
+	}, nil).Once()
+	postgreSQLMock.On("DropIndexConcurrently", config.Schema, "leftover").Return(ErrFake).Once()
+
+	err := checker.RepairIndexes()
+
+	assert.ErrorIs(t, err, ppm.ErrIndexRepairFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
@@ -0,0 +1,159 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var (
+	ErrPartitionRenameFailed = errors.New("at least one partition could not be renamed")
+	ErrNameCollision         = errors.New("canonical name is already used")
+)
+
+// PartitionRename is an attached partition whose name does not follow the naming convention
+type PartitionRename struct {
+	Name      string // partition configuration name
+	Partition partition.Partition
+	NewName   string
+	Err       error // reason the partition can't be renamed, nil when it can
+}
+
+// PlanRenames lists the attached partitions of the partition set, or of all partition sets when name is empty,
+// whose name differs from the canonical name computed from their bounds
+func (p PPM) PlanRenames(name string) ([]PartitionRename, error) {
+	names := slices.Sorted(maps.Keys(p.partitions))
+	if name != "" {
+		names = []string{name}
+	}
+
+	var renames []PartitionRename
+
+	for _, name := range names {
+		config, err := p.getConfiguration(name)
+		if err != nil {
+			return nil, err
+		}
+
+		planned, err := p.planPartitionSetRenames(name, config)
+		if err != nil {
+			return nil, err
+		}
+
+		renames = append(renames, planned...)
+	}
+
+	return renames, nil
+}
+
+func (p PPM) planPartitionSetRenames(name string, config partition.Configuration) ([]PartitionRename, error) {
+	foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return nil, fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	targets := make(map[string]int)
+
+	var renames []PartitionRename
+
+	for _, part := range foundPartitions {
+		newName, err := canonicalName(config, part)
+		if err != nil {
+			return nil, err
+		}
+
+		targets[newName]++
+
+		if newName != part.Name {
+			renames = append(renames, PartitionRename{Name: name, Partition: part, NewName: newName})
+		}
+	}
+
+	for i, rename := range renames {
+		switch {
+		case len(rename.NewName) > partition.MaxIdentifierLength:
+			renames[i].Err = fmt.Errorf("%w: %s", ErrIdentifierTooLong, rename.NewName)
+		case targets[rename.NewName] > 1:
+			renames[i].Err = fmt.Errorf("%w: several partitions have the canonical name %s", ErrNameCollision, rename.NewName)
+		default:
+			exists, err := p.db.IsTableExists(rename.Partition.Schema, rename.NewName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check if table exists: %w", err)
+			}
+
+			if exists {
+				renames[i].Err = fmt.Errorf("%w: table %s already exists", ErrNameCollision, rename.NewName)
+			}
+		}
+	}
+
+	return renames, nil
+}
+
+// canonicalName returns the name of the partition following the naming convention for its bounds.
+// Partitions whose bounds don't follow the interval get a segment name.
+func canonicalName(config partition.Configuration, part partition.Partition) (string, error) {
+	expected, err := config.GeneratePartition(part.LowerBound)
+	if err != nil {
+		return "", fmt.Errorf("could not generate partition: %w", err)
+	}
+
+	if expected.LowerBound.Equal(part.LowerBound) && expected.UpperBound.Equal(part.UpperBound) {
+		return expected.Name, nil
+	}
+
+	return config.SegmentName(part.LowerBound, part.UpperBound), nil
+}
+
+// RenamePartitions renames the partitions to their canonical name, one at a time.
+// A rename waiting longer than the lock timeout is retried, so it never blocks the table for long.
+func (p PPM) RenamePartitions(renames []PartitionRename) error {
+	renameFailed := false
+
+	for _, rename := range renames {
+		if rename.Err != nil {
+			renameFailed = true
+
+			p.logger.Error("Partition can't be renamed", "schema", rename.Partition.Schema, "table", rename.Partition.Name, "new_name", rename.NewName, "error", rename.Err)
+
+			continue
+		}
+
+		config, err := p.getConfiguration(rename.Name)
+		if err != nil {
+			return err
+		}
+
+		err = p.renamePartition(config, rename.Partition, rename.NewName)
+		if err != nil {
+			renameFailed = true
+
+			p.logger.Error("Failed to rename partition", "schema", rename.Partition.Schema, "table", rename.Partition.Name, "new_name", rename.NewName, "error", err)
+
+			continue
+		}
+
+		p.logger.Info("Partition renamed", "schema", rename.Partition.Schema, "table", rename.Partition.Name, "new_name", rename.NewName)
+	}
+
+	if renameFailed {
+		return ErrPartitionRenameFailed
+	}
+
+	return nil
+}
+
+// renamePartition renames the partition and the indexes created by PPM on it in a single transaction
+func (p PPM) renamePartition(config partition.Configuration, part partition.Partition, newName string) error {
+	indexes, err := p.partitionIndexRenames(config, part, newName)
+	if err != nil {
+		return err
+	}
+
+	return p.retryOnLockTimeout("renaming partition", part, func() error {
+		return p.db.RenameTableAndIndexes(part.Schema, part.Name, newName, indexes)
+	})
+}
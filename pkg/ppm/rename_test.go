@@ -0,0 +1,104 @@
+package ppm_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanRenames(t *testing.T) {
+	config := OneDayPartitionConfiguration
+
+	canonical, _ := config.GeneratePartition(time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC))
+	legacy, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+	legacy.Name = "my_table_20240613"
+	segment, _ := config.GeneratePartition(time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC))
+	segment.Name = "my_table_20240614_20240615"
+	colliding, _ := config.GeneratePartition(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	colliding.Name = "my_table_legacy"
+	existing := []partition.Partition{canonical, legacy, segment, colliding}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("IsTableExists", config.Schema, "my_table_2024_06_13").Return(false, nil).Once()
+	postgreSQLMock.On("IsTableExists", config.Schema, "my_table_2024_06_14").Return(false, nil).Once()
+	postgreSQLMock.On("IsTableExists", config.Schema, "my_table_2024_06_15").Return(true, nil).Once()
+
+	renames, err := checker.PlanRenames("my_table")
+	assert.Nil(t, err, "PlanRenames should succeed")
+	assert.Len(t, renames, 3)
+
+	assert.Equal(t, "my_table_2024_06_13", renames[0].NewName)
+	assert.Nil(t, renames[0].Err)
+	assert.Equal(t, "my_table_2024_06_14", renames[1].NewName)
+	assert.Nil(t, renames[1].Err)
+	assert.Equal(t, "my_table_2024_06_15", renames[2].NewName)
+	assert.ErrorIs(t, renames[2].Err, ppm.ErrNameCollision)
+
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestPlanRenamesLongTable(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Table = strings.Repeat("t", 60)
+
+	part, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+	canonicalName := part.Name
+
+	// Created before names were shortened, PostgreSQL truncated the name
+	part.Name = partition.TruncatedIdentifier(config.Table + "_2024_06_13")
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{part}), nil).Once()
+	postgreSQLMock.On("IsTableExists", config.Schema, canonicalName).Return(false, nil).Once()
+
+	renames, err := checker.PlanRenames("")
+	assert.Nil(t, err, "PlanRenames should succeed")
+	assert.Len(t, renames, 1)
+	assert.Nil(t, renames[0].Err)
+	assert.LessOrEqual(t, len(renames[0].NewName), partition.MaxIdentifierLength, "Canonical names are shortened to fit")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestRenamePartitions(t *testing.T) {
+	config := OneDayPartitionConfiguration
+
+	locked, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+	locked.Name = "my_table_20240613"
+	failing, _ := config.GeneratePartition(time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC))
+	failing.Name = "my_table_20240614"
+	colliding, _ := config.GeneratePartition(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+
+	lockTimeout := &pgconn.PgError{Code: ppm.LockNotAvailablePostgreSQLErrorCode}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	// Indexes rolled out by PPM are renamed with the partition, other indexes keep their name
+	postgreSQLMock.On("ListPartitionedIndexes", config.Schema, config.Table).Return([]postgresql.PartitionedIndex{{Name: "created_at_idx"}}, nil).Twice()
+	postgreSQLMock.On("ListTableIndexes", locked.Schema, locked.Name).Return([]postgresql.PartitionedIndex{{Name: "my_table_20240613_created_at_idx"}, {Name: "my_table_20240613_pkey"}}, nil).Once()
+	postgreSQLMock.On("ListTableIndexes", failing.Schema, failing.Name).Return(nil, nil).Once()
+
+	indexes := []postgresql.IndexRename{{Name: "my_table_20240613_created_at_idx", NewName: "my_table_2024_06_13_created_at_idx"}}
+
+	// Lock timeouts are retried, other errors are not
+	postgreSQLMock.On("RenameTableAndIndexes", locked.Schema, locked.Name, "my_table_2024_06_13", indexes).Return(lockTimeout).Once()
+	postgreSQLMock.On("RenameTableAndIndexes", locked.Schema, locked.Name, "my_table_2024_06_13", indexes).Return(nil).Once()
+	postgreSQLMock.On("RenameTableAndIndexes", failing.Schema, failing.Name, "my_table_2024_06_14", []postgresql.IndexRename(nil)).Return(ErrFake).Once()
+
+	err := checker.RenamePartitions([]ppm.PartitionRename{
+		{Name: "unittest", Partition: locked, NewName: "my_table_2024_06_13"},
+		{Name: "unittest", Partition: failing, NewName: "my_table_2024_06_14"},
+		{Name: "unittest", Partition: colliding, NewName: "my_table_2024_06_15", Err: ppm.ErrNameCollision},
+	})
+
+	assert.ErrorIs(t, err, ppm.ErrPartitionRenameFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
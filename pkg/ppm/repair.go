@@ -0,0 +1,208 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var ErrPartitionRepairFailed = errors.New("at least one gap could not be repaired")
+
+// GapRepair is a partition filling a gap between the existing partitions of a partition set
+type GapRepair struct {
+	Name      string // partition configuration name
+	Partition partition.Partition
+}
+
+// FindGapRepairs lists the partitions filling the gaps between the existing partitions of each partition set.
+// Partition sets accepting gaps, such as sparse ones, are skipped.
+func (p PPM) FindGapRepairs() ([]GapRepair, error) {
+	var repairs []GapRepair
+
+	for _, name := range slices.Sorted(maps.Keys(p.partitions)) {
+		config := p.partitions[name]
+
+		if config.AllowsGaps() {
+			p.logger.Debug("Partition set accepts gaps, skip", "schema", config.Schema, "table", config.Table)
+
+			continue
+		}
+
+		// The default partition is handled by the repair, other commands refuse it
+		rawPartitions, err := p.db.ListBoundedPartitions(config.Schema, config.Table)
+		if err != nil {
+			return nil, fmt.Errorf("could not list partitions: %w", err)
+		}
+
+		foundPartitions, err := toPartitions(rawPartitions)
+		if err != nil {
+			return nil, err
+		}
+
+		slices.SortFunc(foundPartitions, func(a, b partition.Partition) int {
+			return a.LowerBound.Compare(b.LowerBound)
+		})
+
+		for i := 1; i < len(foundPartitions); i++ {
+			gap := partition.Bounds(foundPartitions[i-1].UpperBound, foundPartitions[i].LowerBound)
+			if !gap.LowerBound.Before(gap.UpperBound) {
+				continue
+			}
+
+			p.logger.Warn("Partition gap found", "schema", config.Schema, "table", config.Table, "range", gap)
+
+			fillers, err := gapPartitions(config, gap)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, part := range fillers {
+				repairs = append(repairs, GapRepair{Name: name, Partition: part})
+			}
+		}
+	}
+
+	return repairs, nil
+}
+
+// gapPartitions returns the partitions covering the gap, following the interval and naming of the configuration.
+// Partitions crossing the gap bounds are cut to the gap and named after their bounds, as provisioning does.
+func gapPartitions(config partition.Configuration, gap partition.PartitionRange) ([]partition.Partition, error) {
+	var partitions []partition.Partition
+
+	for at := gap.LowerBound; at.Before(gap.UpperBound); {
+		part, err := config.GeneratePartition(at)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate partition: %w", err)
+		}
+
+		if part.LowerBound.Before(gap.LowerBound) || part.UpperBound.After(gap.UpperBound) {
+			part.LowerBound = latest(part.LowerBound, gap.LowerBound)
+			part.UpperBound = earliest(part.UpperBound, gap.UpperBound)
+			part.Name = config.SegmentName(part.LowerBound, part.UpperBound)
+		}
+
+		partitions = append(partitions, part)
+		at = part.UpperBound
+	}
+
+	return partitions, nil
+}
+
+// RepairGaps creates the partitions filling the gaps.
+// Rows of the default partition within the bounds of a created partition are moved to it before the partition is attached.
+func (p PPM) RepairGaps(repairs []GapRepair) error {
+	repairFailed := false
+
+	for _, repair := range repairs {
+		config, err := p.getConfiguration(repair.Name)
+		if err != nil {
+			return err
+		}
+
+		err = p.repairGap(config, repair.Partition)
+		if err != nil {
+			repairFailed = true
+
+			p.logger.Error("Failed to repair gap", "schema", repair.Partition.Schema, "table", repair.Partition.Name, "error", err)
+
+			continue
+		}
+
+		p.logger.Info("Gap repaired", "schema", repair.Partition.Schema, "table", repair.Partition.Name, "range", partition.Bounds(repair.Partition.LowerBound, repair.Partition.UpperBound))
+	}
+
+	if repairFailed {
+		return ErrPartitionRepairFailed
+	}
+
+	return nil
+}
+
+func (p PPM) repairGap(config partition.Configuration, part partition.Partition) error {
+	defaultSchema, defaultTable, err := p.db.GetDefaultPartition(part.Schema, part.ParentTable)
+	if err != nil {
+		return fmt.Errorf("could not get default partition: %w", err)
+	}
+
+	if defaultTable == "" {
+		return p.CreatePartition(config, part)
+	}
+
+	return p.attachWithDefaultRows(config, part, defaultSchema, defaultTable)
+}
+
+// attachWithDefaultRows creates the partition table, then moves the rows of the default partition within its bounds and attaches it in a single transaction.
+// Attaching the partition would otherwise fail, as the default partition would contain rows of the new partition.
+func (p PPM) attachWithDefaultRows(config partition.Configuration, part partition.Partition, defaultSchema, defaultTable string) error {
+	err := checkIdentifier(part.Name)
+	if err != nil {
+		return err
+	}
+
+	_, partitionKey, err := p.db.GetPartitionSettings(part.Schema, part.ParentTable)
+	if err != nil {
+		return fmt.Errorf("failed to get partition settings: %w", err)
+	}
+
+	keyType, err := p.db.GetColumnDataType(part.Schema, part.ParentTable, partitionKey)
+	if err != nil {
+		return fmt.Errorf("failed to get partition key details: %w", err)
+	}
+
+	lowerBound, err := formatBound(keyType, part.LowerBound)
+	if err != nil {
+		return err
+	}
+
+	upperBound, err := formatBound(keyType, part.UpperBound)
+	if err != nil {
+		return err
+	}
+
+	exists, err := p.db.IsTableExists(part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check if table exists: %w", err)
+	}
+
+	if !exists {
+		err = p.db.CreateTableLikeTable(part.Schema, part.Name, part.ParentTable)
+		if err != nil {
+			return fmt.Errorf("failed to create table: %w", err)
+		}
+	}
+
+	err = p.db.AttachPartitionWithDefaultRows(part.Schema, part.Name, part.ParentTable, defaultSchema, defaultTable, partitionKey, lowerBound, upperBound)
+	if err != nil {
+		return fmt.Errorf("failed to move rows from the default partition: %w", err)
+	}
+
+	p.logger.Info("Partition attached with the rows of the default partition", "schema", part.Schema, "table", part.Name, "default_partition", defaultTable)
+
+	err = p.db.SetPartitionReplicaIdentity(part.Schema, part.Name, part.ParentTable)
+	if err != nil {
+		return fmt.Errorf("failed to set replica identity: %w", err)
+	}
+
+	return p.addToPublications(config, part)
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,99 @@
+package ppm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindGapRepairs(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	daily := OneDayPartitionConfiguration
+
+	monthly := OneDayPartitionConfiguration
+	monthly.Table = "my_monthly_table"
+	monthly.Interval = partition.Monthly
+
+	sparse := OneDayPartitionConfiguration
+	sparse.Table = "my_sparse_table"
+	sparse.Sparse = true
+
+	dayBeforeYesterdayPartition, _ := daily.GeneratePartition(workDate.AddDate(0, 0, -4))
+	yesterdayPartition, _ := daily.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := daily.GeneratePartition(workDate)
+	missingFirst, _ := daily.GeneratePartition(workDate.AddDate(0, 0, -3))
+	missingSecond, _ := daily.GeneratePartition(workDate.AddDate(0, 0, -2))
+
+	january, _ := monthly.GeneratePartition(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	segment := partition.Partition{
+		Schema: monthly.Schema, Name: "my_monthly_table_20240315_20240401", ParentTable: monthly.Table,
+		LowerBound: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), UpperBound: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+	february, _ := monthly.GeneratePartition(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	marchStart := partition.Partition{
+		Schema: monthly.Schema, Name: "my_monthly_table_20240301_20240315", ParentTable: monthly.Table,
+		LowerBound: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), UpperBound: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	logger, postgreSQLMock := setupMocks(t)
+
+	postgreSQLMock.On("ListBoundedPartitions", daily.Schema, daily.Table).Return(partitionResultToPartition(t, []partition.Partition{currentPartition, dayBeforeYesterdayPartition, yesterdayPartition}), nil).Once()
+	postgreSQLMock.On("ListBoundedPartitions", monthly.Schema, monthly.Table).Return(partitionResultToPartition(t, []partition.Partition{january, segment}), nil).Once()
+
+	configurations := map[string]partition.Configuration{"daily": daily, "monthly": monthly, "sparse": sparse}
+	checker := ppm.New(context.TODO(), *logger, postgreSQLMock, configurations, workDate)
+
+	repairs, err := checker.FindGapRepairs()
+	assert.Nil(t, err, "FindGapRepairs should succeed")
+
+	// Partitions returned by the mock have no metadata
+	for i := range repairs {
+		repairs[i].Partition.Metadata = partition.Metadata{}
+	}
+
+	assert.Equal(t, []ppm.GapRepair{
+		{Name: "daily", Partition: missingFirst},
+		{Name: "daily", Partition: missingSecond},
+		{Name: "monthly", Partition: february},
+		{Name: "monthly", Partition: marchStart},
+	}, repairs)
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestRepairGapsMovesDefaultPartitionRows(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	part, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("GetDefaultPartition", part.Schema, part.ParentTable).Return(part.Schema, "my_table_default", nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", part.Schema, part.ParentTable).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", part.Schema, part.ParentTable, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("IsTableExists", part.Schema, part.Name).Return(false, nil).Once()
+	postgreSQLMock.On("CreateTableLikeTable", part.Schema, part.Name, part.ParentTable).Return(nil).Once()
+	postgreSQLMock.On("AttachPartitionWithDefaultRows", part.Schema, part.Name, part.ParentTable, part.Schema, "my_table_default", config.PartitionKey, "2024-06-13", "2024-06-14").Return(nil).Once()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", part.Schema, part.Name, part.ParentTable).Return(nil).Once()
+
+	err := checker.RepairGaps([]ppm.GapRepair{{Name: "unittest", Partition: part}})
+	assert.Nil(t, err, "RepairGaps should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestRepairGapsFailure(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	part, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("GetDefaultPartition", part.Schema, part.ParentTable).Return("", "", ErrFake).Once()
+
+	err := checker.RepairGaps([]ppm.GapRepair{{Name: "unittest", Partition: part}})
+	assert.ErrorIs(t, err, ppm.ErrPartitionRepairFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
@@ -0,0 +1,191 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/archive"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var (
+	ErrArchiveNotConfigured     = errors.New("archive is not configured for the partition set")
+	ErrNoArchiveFound           = errors.New("no archived partition found in the range")
+	ErrPartitionRestoreFailed   = errors.New("at least one partition could not be restored")
+	ErrRestoredRowCountMismatch = errors.New("restored row count does not match the archive")
+	ErrTableAlreadyExists       = errors.New("table already exists and is not attached")
+)
+
+// RestorePartitions recreates the archived partitions overlapping the from and to dates, and attaches them to the parent table.
+// Restored partitions are held for holdDays days, so cleanup does not remove them while they are outside of the retention.
+func (p PPM) RestorePartitions(name string, from, to time.Time, holdDays int) error {
+	config, err := p.getConfiguration(name)
+	if err != nil {
+		return err
+	}
+
+	if config.Archive == nil {
+		p.logger.Error("Archive is not configured", "schema", config.Schema, "table", config.Table)
+
+		return ErrArchiveNotConfigured
+	}
+
+	storage, err := archive.NewStorage(config.Archive.Destination)
+	if err != nil {
+		return fmt.Errorf("failed to initialize archive storage: %w", err)
+	}
+
+	manifests, err := archive.ListManifests(storage)
+	if err != nil {
+		return fmt.Errorf("failed to list archives: %w", err)
+	}
+
+	var selected []archive.Manifest
+
+	for _, manifest := range manifests {
+		if manifest.Schema == config.Schema && manifest.ParentTable == config.Table &&
+			!manifest.LowerBound.After(to) && manifest.UpperBound.After(from) {
+			selected = append(selected, manifest)
+		}
+	}
+
+	if len(selected) == 0 {
+		p.logger.Error("No archived partition found", "schema", config.Schema, "table", config.Table, "from", from, "to", to)
+
+		return ErrNoArchiveFound
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].LowerBound.Before(selected[j].LowerBound)
+	})
+
+	metadata := partition.Metadata{HoldUntil: p.workDate.AddDate(0, 0, holdDays)}
+	restoreFailed := false
+
+	for i, manifest := range selected {
+		progress := fmt.Sprintf("%d/%d", i+1, len(selected))
+
+		err = p.restorePartition(config, storage, manifest, metadata)
+		if err != nil {
+			restoreFailed = true
+
+			p.logger.Error("Failed to restore partition", "error", err, "schema", manifest.Schema, "table", manifest.Table, "progress", progress)
+
+			continue
+		}
+
+		p.logger.Info("Partition restored", "schema", manifest.Schema, "table", manifest.Table, "rows", manifest.Rows, "hold_until", metadata.HoldUntil, "progress", progress)
+	}
+
+	if restoreFailed {
+		return ErrPartitionRestoreFailed
+	}
+
+	return nil
+}
+
+func (p PPM) restorePartition(config partition.Configuration, storage archive.Storage, manifest archive.Manifest, metadata partition.Metadata) error {
+	part := partition.Partition{
+		Schema:      manifest.Schema,
+		Name:        manifest.Table,
+		ParentTable: manifest.ParentTable,
+		LowerBound:  manifest.LowerBound,
+		UpperBound:  manifest.UpperBound,
+		Metadata:    metadata,
+	}
+
+	exists, err := p.db.IsTableExists(part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check if table exists: %w", err)
+	}
+
+	if exists {
+		attached, err := p.db.IsPartitionAttached(part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check partition attachment status: %w", err)
+		}
+
+		if !attached {
+			return fmt.Errorf("%w: %s", ErrTableAlreadyExists, part.Name)
+		}
+
+		p.logger.Info("Partition already exists, skip", "schema", part.Schema, "table", part.Name)
+
+		return nil
+	}
+
+	err = archive.Verify(storage, manifest)
+	if err != nil {
+		return fmt.Errorf("archive verification failed: %w", err)
+	}
+
+	// Defaults, constraints and indexes are not archived, the parent table provides them once the partition is attached
+	err = p.db.CreateTableFromDefinition(manifest.Columns)
+	if err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	err = p.loadArchive(storage, manifest)
+	if err == nil {
+		err = p.setMetadata(part)
+	}
+
+	if err == nil {
+		// The table already exists, CreatePartition only attaches it
+		err = p.CreatePartition(config, part)
+	}
+
+	if err != nil {
+		// Drop the partial table so the restore can be retried
+		if dropErr := p.db.DropTable(part.Schema, part.Name); dropErr != nil {
+			p.logger.Error("Failed to drop partially restored table", "error", dropErr, "schema", part.Schema, "table", part.Name)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (p PPM) loadArchive(storage archive.Storage, manifest archive.Manifest) error {
+	file, err := storage.Open(manifest.File)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	reader, err := archive.NewDecompressor(file, manifest.Compression)
+	if err != nil {
+		return fmt.Errorf("failed to decompress archive: %w", err)
+	}
+	defer reader.Close() //nolint:errcheck
+
+	rows, err := p.db.CopyTableFrom(manifest.Schema, manifest.Table, string(manifest.Format), reader)
+	if err != nil {
+		return fmt.Errorf("failed to import archive: %w", err)
+	}
+
+	if rows != manifest.Rows {
+		return fmt.Errorf("%w: %d rows archived, %d rows restored", ErrRestoredRowCountMismatch, manifest.Rows, rows)
+	}
+
+	return nil
+}
+
+// setMetadata stores the partition metadata in the table comment, keeping the other fields of the comment.
+// The comment is removed when it only contained metadata and the metadata is empty.
+func (p PPM) setMetadata(part partition.Partition) error {
+	comment, err := part.Metadata.MergeComment(part.Comment)
+	if err != nil {
+		return err
+	}
+
+	err = p.db.SetTableComment(part.Schema, part.Name, comment)
+	if err != nil {
+		return fmt.Errorf("failed to set partition metadata: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package ppm_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/archive"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const archivedRows = "id,created_at\n1,2024-01-10\n2,2024-01-10\n"
+
+func writeTestArchive(t *testing.T, storage archive.Storage, part partition.Partition) {
+	t.Helper()
+
+	manifest := archive.Manifest{
+		Schema:      part.Schema,
+		Table:       part.Name,
+		ParentTable: part.ParentTable,
+		LowerBound:  part.LowerBound,
+		UpperBound:  part.UpperBound,
+		File:        archive.FileName(part.Schema, part.Name, partition.CSV, partition.Zstd),
+		Format:      partition.CSV,
+		Compression: partition.Zstd,
+		Rows:        2,
+		Columns:     "CREATE TABLE public." + part.Name + " (id bigint, created_at date)",
+	}
+
+	writer, err := archive.NewWriter(storage, manifest.File, manifest.Compression)
+	assert.Nil(t, err, "NewWriter should succeed")
+
+	_, err = io.WriteString(writer, archivedRows)
+	assert.Nil(t, err, "Write should succeed")
+	assert.Nil(t, writer.Close(), "Close should succeed")
+
+	manifest.Checksum = writer.Checksum()
+
+	assert.Nil(t, archive.WriteManifest(storage, manifest), "WriteManifest should succeed")
+}
+
+func TestRestorePartitions(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	archived, _ := config.GeneratePartition(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))
+	outOfRange, _ := config.GeneratePartition(time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name         string
+		restoredRows int64
+		success      bool
+	}{
+		{"Restore archived partition", 2, true},
+		{"Drop partition on row count mismatch", 1, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			directory := t.TempDir()
+			storage := archive.NewLocalStorage(directory)
+
+			writeTestArchive(t, storage, archived)
+			writeTestArchive(t, storage, outOfRange)
+
+			config.Archive = &partition.ArchiveConfiguration{Destination: directory}
+
+			checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+			postgreSQLMock.On("IsTableExists", archived.Schema, archived.Name).Return(false, nil).Once()
+			postgreSQLMock.On("CreateTableFromDefinition", "CREATE TABLE public."+archived.Name+" (id bigint, created_at date)").Return(nil).Once()
+			postgreSQLMock.On("CopyTableFrom", archived.Schema, archived.Name, "csv", mock.Anything).Run(func(args mock.Arguments) {
+				r, _ := args.Get(3).(io.Reader)
+				data, err := io.ReadAll(r)
+				assert.Nil(t, err, "Archive should be readable")
+				assert.Equal(t, archivedRows, string(data))
+			}).Return(tc.restoredRows, nil).Once()
+
+			if tc.success {
+				postgreSQLMock.On("SetTableComment", archived.Schema, archived.Name, `{"holdUntil":"2024-06-22T00:00:00Z"}`).Return(nil).Once()
+				postgreSQLMock.On("GetPartitionSettings", archived.Schema, archived.ParentTable).Return(string(partition.Range), config.PartitionKey, nil).Once()
+				postgreSQLMock.On("GetColumnDataType", archived.Schema, archived.ParentTable, config.PartitionKey).Return(postgresql.Date, nil).Once()
+				postgreSQLMock.On("IsTableExists", archived.Schema, archived.Name).Return(true, nil).Once()
+				postgreSQLMock.On("IsPartitionAttached", archived.Schema, archived.Name).Return(false, nil).Once()
+				postgreSQLMock.On("AttachPartition", archived.Schema, archived.Name, archived.ParentTable, "2024-01-10", "2024-01-11").Return(nil).Once()
+				postgreSQLMock.On("SetPartitionReplicaIdentity", archived.Schema, archived.Name, archived.ParentTable).Return(nil).Once()
+			} else {
+				postgreSQLMock.On("DropTable", archived.Schema, archived.Name).Return(nil).Once()
+			}
+
+			err := checker.RestorePartitions("unittest", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 7)
+
+			if tc.success {
+				assert.Nil(t, err, "RestorePartitions should succeed")
+			} else {
+				assert.ErrorIs(t, err, ppm.ErrPartitionRestoreFailed)
+			}
+
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestorePartitionsWithoutArchive(t *testing.T) {
+	checker, _ := setupPPM(t, OneDayPartitionConfiguration, time.Now())
+
+	err := checker.RestorePartitions("unittest", yesterday, today, 7)
+	assert.ErrorIs(t, err, ppm.ErrArchiveNotConfigured)
+
+	config := OneDayPartitionConfiguration
+	config.Archive = &partition.ArchiveConfiguration{Destination: t.TempDir()}
+	checker, _ = setupPPM(t, config, time.Now())
+
+	err = checker.RestorePartitions("unittest", yesterday, today, 7)
+	assert.ErrorIs(t, err, ppm.ErrNoArchiveFound)
+}
+
+func TestHeldPartitionsAreKept(t *testing.T) {
+	config := OneDayPartitionConfiguration
+
+	restored, _ := config.GeneratePartition(dayBeforeYesterday.AddDate(0, 0, -10))
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+
+	existing := partitionResultToPartition(t, []partition.Partition{restored, yesterdayPartition, currentPartition, tomorrowPartition})
+	existing[0].Comment = `{"holdUntil":"` + tomorrow.Format(time.RFC3339) + `"}`
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(existing, nil)
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+
+	assert.Nil(t, checker.ProvisioningPartitions(), "Provisioning should ignore held partitions")
+	assert.Nil(t, checker.CleanupPartitions(), "Cleanup should keep held partitions")
+	assert.Nil(t, checker.CheckPartitions(), "Check should ignore held partitions")
+
+	postgreSQLMock.AssertExpectations(t)
+}
@@ -0,0 +1,123 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+)
+
+var ErrIndexRolloutFailed = errors.New("index could not be rolled out on at least one partition")
+
+// RolloutIndex creates an index on a partitioned table without long locks.
+// The index is created on the parent table only, then built concurrently on each partition and attached to the parent index.
+// Partitions already attached to the parent index are skipped, so the rollout can be resumed after an interruption.
+func (p PPM) RolloutIndex(name, index, definition string) error {
+	config, err := p.getConfiguration(name)
+	if err != nil {
+		return err
+	}
+
+	err = p.db.CreateIndexOnParent(config.Schema, config.Table, index, definition)
+	if err != nil {
+		p.logger.Error("Failed to create index on parent table", "error", err, "schema", config.Schema, "table", config.Table, "index", index)
+
+		return fmt.Errorf("failed to create index on parent table: %w", err)
+	}
+
+	foundPartitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	parentIndex := postgresql.PartitionedIndex{Name: index, Definition: definition}
+	rolloutFailed := false
+
+	for i, part := range foundPartitions {
+		progress := fmt.Sprintf("%d/%d", i+1, len(foundPartitions))
+
+		err = p.rolloutPartitionIndex(part, parentIndex)
+		if err != nil {
+			rolloutFailed = true
+
+			p.logger.Error("Failed to roll out index on partition", "error", err, "schema", part.Schema, "table", part.Name, "index", index, "progress", progress)
+
+			continue
+		}
+
+		p.logger.Info("Index rolled out on partition", "schema", part.Schema, "table", part.Name, "index", index, "progress", progress)
+	}
+
+	if rolloutFailed {
+		return ErrIndexRolloutFailed
+	}
+
+	p.logger.Info("Index rolled out on all partitions", "schema", config.Schema, "table", config.Table, "index", index)
+
+	return nil
+}
+
+func (p PPM) rolloutPartitionIndex(part partition.Partition, parentIndex postgresql.PartitionedIndex) error {
+	attached, err := p.db.IsIndexPartitionAttached(part.Schema, parentIndex.Name, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check index attachment: %w", err)
+	}
+
+	if attached {
+		p.logger.Debug("Partition index is already attached, skip", "schema", part.Schema, "table", part.Name, "index", parentIndex.Name)
+
+		return nil
+	}
+
+	indexName := partition.PartitionIndexName(part, parentIndex.Name)
+
+	err = p.migrateLegacyIndex(part, parentIndex.Name)
+	if err != nil {
+		return err
+	}
+
+	status, err := p.db.GetIndexStatus(part.Schema, indexName)
+	if err != nil {
+		return fmt.Errorf("failed to get index status: %w", err)
+	}
+
+	if status.Exists && !status.Valid {
+		// Leftover of an interrupted concurrent build
+		p.logger.Warn("Invalid index found, rebuild it", "schema", part.Schema, "table", part.Name, "index", indexName)
+
+		err = p.db.DropIndexConcurrently(part.Schema, indexName)
+		if err != nil {
+			return fmt.Errorf("failed to drop invalid index: %w", err)
+		}
+
+		status.Exists = false
+	}
+
+	if !status.Exists {
+		if parentIndex.Unique {
+			err = p.db.CreateUniqueIndexConcurrently(part.Schema, part.Name, indexName, parentIndex.Definition)
+		} else {
+			err = p.db.CreateIndexConcurrently(part.Schema, part.Name, indexName, parentIndex.Definition)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	// A partition index can only be attached to a constraint index once it is used by the same kind of constraint
+	if parentIndex.Constraint != "" && !status.Constraint {
+		err = p.db.AddIndexConstraint(part.Schema, part.Name, indexName, parentIndex.Constraint)
+		if err != nil {
+			return fmt.Errorf("failed to add index constraint: %w", err)
+		}
+	}
+
+	err = p.db.AttachIndex(part.Schema, parentIndex.Name, indexName)
+	if err != nil {
+		return fmt.Errorf("failed to attach index: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolloutIndex(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	index := "my_table_customer_idx"
+	definition := "(customer_id)"
+
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	existing := []partition.Partition{yesterdayPartition, currentPartition, tomorrowPartition}
+	postgreSQLMock.On("CreateIndexOnParent", config.Schema, config.Table, index, definition).Return(nil).Once()
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	// Yesterday partition was processed by a previous run
+	postgreSQLMock.On("IsIndexPartitionAttached", config.Schema, index, yesterdayPartition.Name).Return(true, nil).Once()
+
+	// Current partition has an invalid index left by an interrupted run
+	currentIndex := partition.PartitionIndexName(currentPartition, index)
+	postgreSQLMock.On("IsIndexPartitionAttached", config.Schema, index, currentPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, currentIndex).Return(postgresql.IndexStatus{Exists: true, Valid: false}, nil).Once()
+	postgreSQLMock.On("DropIndexConcurrently", config.Schema, currentIndex).Return(nil).Once()
+	postgreSQLMock.On("CreateIndexConcurrently", config.Schema, currentPartition.Name, currentIndex, definition).Return(nil).Once()
+	postgreSQLMock.On("AttachIndex", config.Schema, index, currentIndex).Return(nil).Once()
+
+	// Tomorrow partition has a valid index that is not attached yet
+	tomorrowIndex := partition.PartitionIndexName(tomorrowPartition, index)
+	postgreSQLMock.On("IsIndexPartitionAttached", config.Schema, index, tomorrowPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, tomorrowIndex).Return(postgresql.IndexStatus{Exists: true, Valid: true}, nil).Once()
+	postgreSQLMock.On("AttachIndex", config.Schema, index, tomorrowIndex).Return(nil).Once()
+
+	err := checker.RolloutIndex("unittest", index, definition)
+
+	assert.Nil(t, err, "RolloutIndex should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestRolloutIndexContinuesOnFailure(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	index := "my_table_customer_idx"
+	definition := "(customer_id)"
+
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	existing := []partition.Partition{currentPartition, tomorrowPartition}
+	postgreSQLMock.On("CreateIndexOnParent", config.Schema, config.Table, index, definition).Return(nil).Once()
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	currentIndex := partition.PartitionIndexName(currentPartition, index)
+	postgreSQLMock.On("IsIndexPartitionAttached", config.Schema, index, currentPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, currentIndex).Return(postgresql.IndexStatus{}, nil).Once()
+	postgreSQLMock.On("CreateIndexConcurrently", config.Schema, currentPartition.Name, currentIndex, definition).Return(ErrFake).Once()
+
+	tomorrowIndex := partition.PartitionIndexName(tomorrowPartition, index)
+	postgreSQLMock.On("IsIndexPartitionAttached", config.Schema, index, tomorrowPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("GetIndexStatus", config.Schema, tomorrowIndex).Return(postgresql.IndexStatus{}, nil).Once()
+	postgreSQLMock.On("CreateIndexConcurrently", config.Schema, tomorrowPartition.Name, tomorrowIndex, definition).Return(nil).Once()
+	postgreSQLMock.On("AttachIndex", config.Schema, index, tomorrowIndex).Return(nil).Once()
+
+	err := checker.RolloutIndex(config.Table, index, definition)
+
+	assert.ErrorIs(t, err, ppm.ErrIndexRolloutFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
@@ -0,0 +1,160 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+var (
+	ErrCleanupRefused           = errors.New("cleanup refused by safeguards")
+	ErrTooManyPartitionsRemoved = errors.New("too many partitions would be removed")
+	ErrPartitionHasRecentRows   = errors.New("partition contains rows newer than the retention cutoff")
+	ErrPartitionTooLarge        = errors.New("partition is larger than the maximum size")
+)
+
+// applySafeguards returns the partitions cleanup is allowed to remove: the oldest removable partitions, up to the first refused one.
+// ErrCleanupRefused is returned with the allowed partitions when a partition is refused.
+// Partitions removed within the retention, by the size budget, are not checked for recent rows as they hold them by design.
+func (p PPM) applySafeguards(config partition.Configuration, foundPartitions, removable []partition.Partition, withinRetention bool) ([]partition.Partition, error) {
+	safeguards := config.Safeguards
+	if safeguards == nil || len(removable) == 0 {
+		return removable, nil
+	}
+
+	reason := checkRemovalLimits(*safeguards, len(foundPartitions), len(removable))
+	if reason != nil && p.refuse(config.Schema, config.Table, reason) {
+		return nil, ErrCleanupRefused
+	}
+
+	maxSize, err := safeguards.GetMaxPartitionSize()
+	if err != nil {
+		return nil, fmt.Errorf("invalid maximum partition size: %w", err)
+	}
+
+	var partitionKey, cutoff string
+
+	if safeguards.CheckRecentRows && !withinRetention {
+		partitionKey, cutoff, err = p.retentionCutoff(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	removable = slices.Clone(removable)
+	slices.SortFunc(removable, func(a, b partition.Partition) int {
+		return a.LowerBound.Compare(b.LowerBound)
+	})
+
+	var allowed []partition.Partition
+
+	for _, part := range removable {
+		reason, err := p.checkPartitionRemoval(part, maxSize, partitionKey, cutoff)
+		if err != nil {
+			return nil, err
+		}
+
+		// Removing newer partitions would leave a hole in the history
+		if reason != nil && p.refuse(part.Schema, part.Name, reason) {
+			return allowed, ErrCleanupRefused
+		}
+
+		allowed = append(allowed, part)
+	}
+
+	return allowed, nil
+}
+
+// checkRemovalLimits returns the reason to refuse removing count partitions out of total in a single run
+func checkRemovalLimits(safeguards partition.SafeguardConfiguration, total, count int) error {
+	if safeguards.MaxPartitions > 0 && count > safeguards.MaxPartitions {
+		return fmt.Errorf("%w: %d partitions, the limit is %d", ErrTooManyPartitionsRemoved, count, safeguards.MaxPartitions)
+	}
+
+	if safeguards.MaxPercent > 0 && count*100 > safeguards.MaxPercent*total {
+		return fmt.Errorf("%w: %d of %d partitions, the limit is %d%%", ErrTooManyPartitionsRemoved, count, total, safeguards.MaxPercent)
+	}
+
+	return nil
+}
+
+// checkPartitionRemoval returns the reason to refuse removing the partition.
+// Size is not checked when maxSize is 0, and rows are not checked when cutoff is empty.
+func (p PPM) checkPartitionRemoval(part partition.Partition, maxSize int64, partitionKey, cutoff string) (reason error, err error) {
+	if maxSize > 0 {
+		size, err := p.db.GetTableSize(part.Schema, part.Name)
+		if err != nil {
+			return nil, fmt.Errorf("could not get partition size: %w", err)
+		}
+
+		if size > maxSize {
+			return fmt.Errorf("%w: %d bytes, the limit is %d bytes", ErrPartitionTooLarge, size, maxSize), nil
+		}
+	}
+
+	if cutoff != "" {
+		found, err := p.db.HasRowsFrom(part.Schema, part.Name, partitionKey, cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("could not look for recent rows: %w", err)
+		}
+
+		if found {
+			return fmt.Errorf("%w: %s >= %s", ErrPartitionHasRecentRows, partitionKey, cutoff), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// retentionCutoff returns the partition key and the lower bound of the retention computed from the server time.
+// The server time is used instead of the work date, so a wrong work date can't move the cutoff.
+func (p PPM) retentionCutoff(config partition.Configuration) (partitionKey, cutoff string, err error) {
+	serverTime, err := p.db.GetServerTime()
+	if err != nil {
+		return "", "", fmt.Errorf("could not get server time: %w", err)
+	}
+
+	retentions, err := config.GetRetentionPartitions(serverTime)
+	if err != nil {
+		return "", "", fmt.Errorf("could not generate retention partitions: %w", err)
+	}
+
+	lowerBound := serverTime
+	for _, part := range retentions {
+		if part.LowerBound.Before(lowerBound) {
+			lowerBound = part.LowerBound
+		}
+	}
+
+	_, partitionKey, err = p.db.GetPartitionSettings(config.Schema, config.Table)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get partition settings: %w", err)
+	}
+
+	keyType, err := p.db.GetColumnDataType(config.Schema, config.Table, partitionKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get partition key details: %w", err)
+	}
+
+	cutoff, err = formatBound(keyType, lowerBound)
+	if err != nil {
+		return "", "", err
+	}
+
+	return partitionKey, cutoff, nil
+}
+
+// refuse reports a safeguard violation and returns true unless safeguards are overridden
+func (p PPM) refuse(schema, table string, reason error) bool {
+	if p.force {
+		p.logger.Warn("Safeguard overridden by force", "schema", schema, "table", table, "reason", reason)
+
+		return false
+	}
+
+	p.logger.Error("Cleanup refused by safeguard, run cleanup with --force to override", "schema", schema, "table", table, "reason", reason)
+
+	return true
+}
@@ -0,0 +1,110 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupRefusesTooManyPartitions(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Safeguards = &partition.SafeguardConfiguration{MaxPartitions: 1}
+
+	dayBeforeYesterdayPartition, _ := config.GeneratePartition(dayBeforeYesterday)
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+	dayAfterTomorrowPartition, _ := config.GeneratePartition(dayAfterTomorrow)
+	existing := []partition.Partition{dayBeforeYesterdayPartition, yesterdayPartition, currentPartition, tomorrowPartition, dayAfterTomorrowPartition}
+	removed := []partition.Partition{dayBeforeYesterdayPartition, dayAfterTomorrowPartition}
+
+	testCases := []struct {
+		name     string
+		force    bool
+		expected []partition.Partition
+	}{
+		{"Refused", false, nil},
+		{"Forced", true, removed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+			postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+			for _, p := range tc.expected {
+				postgreSQLMock.On("DetachPartitionConcurrently", p.Schema, p.Name, p.ParentTable).Return(nil).Once()
+				postgreSQLMock.On("DropTable", p.Schema, p.Name).Return(nil).Once()
+			}
+
+			checker.SetForce(tc.force)
+			err := checker.CleanupPartitions()
+
+			if tc.force {
+				assert.Nil(t, err, "CleanupPartitions should succeed")
+			} else {
+				assert.ErrorIs(t, err, ppm.ErrPartitionCleanupFailed)
+			}
+
+			postgreSQLMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCleanupRefusesPartitionsWithRecentRows(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Safeguards = &partition.SafeguardConfiguration{CheckRecentRows: true}
+
+	serverTime := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	wrongWorkDate := serverTime.AddDate(0, 1, 0)
+
+	empty, _ := config.GeneratePartition(serverTime.AddDate(0, 0, -2))
+	recent, _ := config.GeneratePartition(serverTime.AddDate(0, 0, -1))
+	existing := []partition.Partition{empty, recent}
+
+	checker, postgreSQLMock := setupPPM(t, config, wrongWorkDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("GetServerTime").Return(serverTime, nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+
+	// The retention cutoff is computed from the server time, not from the work date
+	postgreSQLMock.On("HasRowsFrom", empty.Schema, empty.Name, config.PartitionKey, "2024-06-14").Return(false, nil).Once()
+	postgreSQLMock.On("HasRowsFrom", recent.Schema, recent.Name, config.PartitionKey, "2024-06-14").Return(true, nil).Once()
+
+	postgreSQLMock.On("DetachPartitionConcurrently", empty.Schema, empty.Name, empty.ParentTable).Return(nil).Once()
+	postgreSQLMock.On("DropTable", empty.Schema, empty.Name).Return(nil).Once()
+
+	err := checker.CleanupPartitions()
+
+	assert.ErrorIs(t, err, ppm.ErrPartitionCleanupFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestCleanupRefusesLargePartitions(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Safeguards = &partition.SafeguardConfiguration{MaxPartitionSize: "1MB"}
+
+	oldest, _ := config.GeneratePartition(dayBeforeYesterday.AddDate(0, 0, -1))
+	removed, _ := config.GeneratePartition(dayBeforeYesterday)
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	existing := []partition.Partition{removed, oldest, yesterdayPartition, currentPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	// The oldest partition is refused, so newer ones are kept to avoid a hole in the history
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("GetTableSize", oldest.Schema, oldest.Name).Return(int64(2*1024*1024), nil).Once()
+
+	err := checker.CleanupPartitions()
+
+	assert.ErrorIs(t, err, ppm.ErrPartitionCleanupFailed)
+	postgreSQLMock.AssertExpectations(t)
+}
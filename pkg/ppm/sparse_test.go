@@ -0,0 +1,116 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparseProvisioning(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.Sparse = true
+
+	// Partition restored out of the retention, with a gap before the retained partitions
+	restored, _ := config.GeneratePartition(workDate.AddDate(0, 0, -10))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	existing := []partition.Partition{restored, yesterdayPartition, currentPartition}
+
+	missing, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", missing.Schema, missing.ParentTable).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", missing.Schema, missing.ParentTable, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("IsTableExists", missing.Schema, missing.Name).Return(false, nil).Once()
+	postgreSQLMock.On("CreateTableLikeTable", missing.Schema, missing.Name, missing.ParentTable).Return(nil).Once()
+	postgreSQLMock.On("IsPartitionAttached", missing.Schema, missing.Name).Return(false, nil).Once()
+	postgreSQLMock.On("AttachPartition", missing.Schema, missing.Name, missing.ParentTable, "2024-06-16", "2024-06-17").Return(nil).Once()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", missing.Schema, missing.Name, missing.ParentTable).Return(nil).Once()
+
+	assert.Nil(t, checker.ProvisioningPartitions(), "Provisioning should create the partitions after the newest one")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestSparseProvisioningKeepsGaps(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.Sparse = true
+
+	// The current partition was dropped on purpose
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{yesterdayPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	assert.Nil(t, checker.ProvisioningPartitions(), "Provisioning should not create partitions in gaps")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestSparseCleanup(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.Sparse = true
+
+	restored, _ := config.GeneratePartition(workDate.AddDate(0, 0, -10))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	existing := []partition.Partition{restored, yesterdayPartition, currentPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("DetachPartitionConcurrently", restored.Schema, restored.Name, restored.ParentTable).Return(nil).Once()
+	postgreSQLMock.On("DropTable", restored.Schema, restored.Name).Return(nil).Once()
+
+	assert.Nil(t, checker.CleanupPartitions(), "Cleanup should remove expired partitions despite gaps")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestSparseCheckAcceptsGaps(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.Sparse = true
+
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{yesterdayPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+
+	assert.Nil(t, checker.CheckPartitions(), "Check should accept gaps between existing partitions")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestSparseCheckReportsMissingPartitions(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.Sparse = true
+
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	existing := []partition.Partition{yesterdayPartition, currentPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+
+	// Partitions after the newest existing one are still expected
+	assert.ErrorIs(t, checker.CheckPartitions(), ppm.ErrInvalidPartitionConfiguration)
+	postgreSQLMock.AssertExpectations(t)
+}
@@ -0,0 +1,251 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+)
+
+var (
+	ErrPartitionSplitFailed = errors.New("partition split failed")
+	ErrInvalidSplit         = errors.New("partition can't be split into the interval")
+	ErrRowCountMismatch     = errors.New("row counts don't match")
+)
+
+// SplitPartition replaces a partition by finer partitions of the into interval.
+// The partition is set read-only, and its rows are copied to the new tables in batches ordered by the partition key.
+// Once row counts are verified, the new tables replace the partition in a single transaction, so queries never miss rows.
+// The original table is dropped, unless keep is set or the partition is held.
+func (p PPM) SplitPartition(name, partitionName string, into partition.Interval, batchSize int, keep bool) error {
+	config, err := p.getConfiguration(name)
+	if err != nil {
+		return err
+	}
+
+	partitions, err := p.ListPartitions(config.Schema, config.Table)
+	if err != nil {
+		return fmt.Errorf("could not list partitions: %w", err)
+	}
+
+	for _, part := range partitions {
+		if part.Name != partitionName {
+			continue
+		}
+
+		err = p.splitPartition(config, part, into, batchSize, keep)
+		if err != nil {
+			p.logger.Error("Failed to split partition", "error", err, "schema", part.Schema, "table", part.Name)
+
+			return fmt.Errorf("%w: %w", ErrPartitionSplitFailed, err)
+		}
+
+		return nil
+	}
+
+	p.logger.Error("Partition not found", "schema", config.Schema, "table", partitionName, "parent_table", config.Table)
+
+	return fmt.Errorf("%w: %s", ErrPartitionNotFound, partitionName)
+}
+
+// splitPartitions returns the partitions of the into interval covering the partition
+func splitPartitions(config partition.Configuration, part partition.Partition, into partition.Interval) ([]partition.Partition, error) {
+	finer := config
+	finer.Interval = into
+
+	targets, err := gapPartitions(finer, partition.Bounds(part.LowerBound, part.UpperBound))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(targets) < 2 { //nolint:mnd // a split creates at least two partitions
+		return nil, fmt.Errorf("%w: %s is not larger than a %s partition", ErrInvalidSplit, part.Name, into)
+	}
+
+	return targets, nil
+}
+
+func (p PPM) splitPartition(config partition.Configuration, part partition.Partition, into partition.Interval, batchSize int, keep bool) error {
+	targets, err := splitPartitions(config, part, into)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		exists, err := p.db.IsTableExists(target.Schema, target.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check if table exists: %w", err)
+		}
+
+		if exists {
+			return fmt.Errorf("%w: table %s already exists", ErrNameCollision, target.Name)
+		}
+	}
+
+	// The partition is read-only during the split, so rows can't be written to it once copied
+	frozen, err := p.setReadOnly([]partition.Partition{part})
+	if err != nil {
+		p.unsetReadOnly(frozen)
+
+		return err
+	}
+
+	copied, err := p.buildSplitPartitions(config, part, targets, batchSize)
+	if err != nil {
+		p.dropCreatedTables(targets)
+		p.unsetReadOnly(frozen)
+
+		return err
+	}
+
+	p.logger.Info("Partition split", "schema", part.Schema, "table", part.Name, "into", into, "partitions", len(targets), "rows", copied)
+
+	for _, target := range targets {
+		p.dropBoundsConstraint(target)
+
+		err = p.db.SetPartitionReplicaIdentity(target.Schema, target.Name, target.ParentTable)
+		if err != nil {
+			return fmt.Errorf("failed to set replica identity: %w", err)
+		}
+
+		err = p.addToPublications(config, target)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.unsetReadOnly(frozen)
+
+	if keep {
+		p.logger.Info("Original partition kept", "schema", part.Schema, "table", part.Name)
+
+		return nil
+	}
+
+	_, err = p.dropReplacedPartitions(config, []partition.Partition{part})
+
+	return err
+}
+
+// buildSplitPartitions copies the rows of the partition to the tables of the new partitions, then replaces the partition by the new tables.
+// It returns the number of rows copied.
+func (p PPM) buildSplitPartitions(config partition.Configuration, part partition.Partition, targets []partition.Partition, batchSize int) (int64, error) {
+	_, partitionKey, err := p.db.GetPartitionSettings(part.Schema, part.ParentTable)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get partition settings: %w", err)
+	}
+
+	keyType, err := p.db.GetColumnDataType(part.Schema, part.ParentTable, partitionKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get partition key details: %w", err)
+	}
+
+	expected, err := p.db.CountRows(part.Schema, part.Name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count rows: %w", err)
+	}
+
+	var total int64
+
+	attachments := make([]postgresql.Attachment, 0, len(targets))
+
+	for _, target := range targets {
+		lowerBound, err := formatBound(keyType, target.LowerBound)
+		if err != nil {
+			return total, err
+		}
+
+		upperBound, err := formatBound(keyType, target.UpperBound)
+		if err != nil {
+			return total, err
+		}
+
+		err = p.db.CreateTableLikeTable(target.Schema, target.Name, target.ParentTable)
+		if err != nil {
+			return total, fmt.Errorf("failed to create table: %w", err)
+		}
+
+		copied, err := p.copyRowsInBatches(part, target, partitionKey, lowerBound, upperBound, batchSize, total)
+		total += copied
+
+		if err != nil {
+			return total, err
+		}
+
+		err = p.db.AddBoundsConstraint(target.Schema, target.Name, boundsConstraint, partitionKey, lowerBound, upperBound)
+		if err != nil {
+			return total, err
+		}
+
+		err = p.createRuleIndexes(config, target)
+		if err != nil {
+			return total, err
+		}
+
+		attachments = append(attachments, postgresql.Attachment{Table: target.Name, LowerBound: lowerBound, UpperBound: upperBound})
+	}
+
+	if total != expected {
+		return total, fmt.Errorf("%w: %d rows in %s, %d rows copied", ErrRowCountMismatch, expected, part.Name, total)
+	}
+
+	for _, target := range targets {
+		err = p.inheritHold([]partition.Partition{part}, target)
+		if err != nil {
+			return total, err
+		}
+	}
+
+	err = p.retryOnLockTimeout("replacing partitions", part, func() error {
+		return p.db.ReplacePartitions(part.Schema, part.ParentTable, []string{part.Name}, attachments)
+	})
+
+	return total, err
+}
+
+// copyRowsInBatches copies the rows of the source within [lowerBound, upperBound) to the target, in batches ordered by the partition key.
+// Batches end on a partition key value, so rows sharing the key of a batch bound are never split between two batches.
+// Progress is logged after each batch, counting the rows copied by previous calls.
+func (p PPM) copyRowsInBatches(source, target partition.Partition, partitionKey, lowerBound, upperBound string, batchSize int, previouslyCopied int64) (int64, error) {
+	var total int64
+
+	for from := lowerBound; from != ""; {
+		to, err := p.db.GetBatchUpperBound(source.Schema, source.Name, partitionKey, from, upperBound, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		batchEnd := to
+		if batchEnd == "" {
+			batchEnd = upperBound
+		}
+
+		copied, err := p.db.CopyRows(source.Schema, source.Name, target.Schema, target.Name, partitionKey, from, batchEnd)
+		if err != nil {
+			return total, err
+		}
+
+		total += copied
+		from = to
+
+		p.logger.Info("Copying rows", "schema", source.Schema, "table", source.Name, "target", target.Name, "copied", previouslyCopied+total)
+	}
+
+	return total, nil
+}
+
+// dropCreatedTables drops the tables created by a split or a merge, so it can be run again
+func (p PPM) dropCreatedTables(targets []partition.Partition) {
+	for _, target := range targets {
+		exists, err := p.db.IsTableExists(target.Schema, target.Name)
+		if err != nil || !exists {
+			continue
+		}
+
+		err = p.DeletePartition(target)
+		if err != nil {
+			p.logger.Warn("Failed to drop created table", "error", err, "schema", target.Schema, "table", target.Name)
+		}
+	}
+}
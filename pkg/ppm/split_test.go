@@ -0,0 +1,167 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm"
+	"github.com/qonto/postgresql-partition-manager/pkg/ppm/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// twoDaysPartition returns a partition covering 2024-06-13 and 2024-06-14, split into my_table_2024_06_13 and my_table_2024_06_14
+func twoDaysPartition(config partition.Configuration) partition.Partition {
+	lowerBound := time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC)
+	upperBound := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	return partition.Partition{
+		Schema:      config.Schema,
+		Name:        config.SegmentName(lowerBound, upperBound),
+		ParentTable: config.Table,
+		LowerBound:  lowerBound,
+		UpperBound:  upperBound,
+	}
+}
+
+// expectSplitCopy expects the partition to be set read-only, and its rows to be copied to the new tables, count being the rows of the partition
+func expectSplitCopy(postgreSQLMock *mocks.PostgreSQLClient, config partition.Configuration, original partition.Partition, count int64) {
+	postgreSQLMock.On("IsTableExists", "public", "my_table_2024_06_13").Return(false, nil).Once()
+	postgreSQLMock.On("IsTableExists", "public", "my_table_2024_06_14").Return(false, nil).Once()
+	postgreSQLMock.On("IsTableReadOnly", "public", original.Name).Return(false, nil).Once()
+	postgreSQLMock.On("SetTableReadOnly", "public", original.Name).Return(nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", "public", config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", "public", config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("CountRows", "public", original.Name).Return(count, nil).Once()
+
+	// The first day is copied in two batches
+	postgreSQLMock.On("CreateTableLikeTable", "public", "my_table_2024_06_13", config.Table).Return(nil).Once()
+	postgreSQLMock.On("GetBatchUpperBound", "public", original.Name, config.PartitionKey, "2024-06-13", "2024-06-14", 100).Return("2024-06-13 12:00:00", nil).Once()
+	postgreSQLMock.On("CopyRows", "public", original.Name, "public", "my_table_2024_06_13", config.PartitionKey, "2024-06-13", "2024-06-13 12:00:00").Return(int64(100), nil).Once()
+	postgreSQLMock.On("GetBatchUpperBound", "public", original.Name, config.PartitionKey, "2024-06-13 12:00:00", "2024-06-14", 100).Return("", nil).Once()
+	postgreSQLMock.On("CopyRows", "public", original.Name, "public", "my_table_2024_06_13", config.PartitionKey, "2024-06-13 12:00:00", "2024-06-14").Return(int64(40), nil).Once()
+	postgreSQLMock.On("AddBoundsConstraint", "public", "my_table_2024_06_13", "ppm_partition_bounds", config.PartitionKey, "2024-06-13", "2024-06-14").Return(nil).Once()
+
+	postgreSQLMock.On("CreateTableLikeTable", "public", "my_table_2024_06_14", config.Table).Return(nil).Once()
+	postgreSQLMock.On("GetBatchUpperBound", "public", original.Name, config.PartitionKey, "2024-06-14", "2024-06-15", 100).Return("", nil).Once()
+	postgreSQLMock.On("CopyRows", "public", original.Name, "public", "my_table_2024_06_14", config.PartitionKey, "2024-06-14", "2024-06-15").Return(int64(60), nil).Once()
+	postgreSQLMock.On("AddBoundsConstraint", "public", "my_table_2024_06_14", "ppm_partition_bounds", config.PartitionKey, "2024-06-14", "2024-06-15").Return(nil).Once()
+
+	// Index rules are built on the new tables before they are attached
+	for _, name := range []string{"my_table_2024_06_13", "my_table_2024_06_14"} {
+		for _, rule := range config.Indexes {
+			target := partition.Partition{Schema: "public", Name: name}
+
+			postgreSQLMock.On("GetIndexStatus", "public", rule.IndexName(target)).Return(postgresql.IndexStatus{}, nil).Once()
+			postgreSQLMock.On("CreateIndexConcurrently", "public", name, rule.IndexName(target), rule.Definition).Return(nil).Once()
+		}
+	}
+}
+
+func TestSplitPartition(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Indexes = []partition.IndexRule{{Name: "brin", Definition: "USING brin (created_at)"}}
+	original := twoDaysPartition(config)
+	lockTimeout := &pgconn.PgError{Code: ppm.LockNotAvailablePostgreSQLErrorCode}
+	attachments := []postgresql.Attachment{
+		{Table: "my_table_2024_06_13", LowerBound: "2024-06-13", UpperBound: "2024-06-14"},
+		{Table: "my_table_2024_06_14", LowerBound: "2024-06-14", UpperBound: "2024-06-15"},
+	}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{original}), nil).Once()
+	expectSplitCopy(postgreSQLMock, config, original, 200)
+
+	// The swap is retried on lock timeout
+	postgreSQLMock.On("ReplacePartitions", "public", config.Table, []string{original.Name}, attachments).Return(lockTimeout).Once()
+	postgreSQLMock.On("ReplacePartitions", "public", config.Table, []string{original.Name}, attachments).Return(nil).Once()
+
+	for _, attachment := range attachments {
+		postgreSQLMock.On("DropConstraint", "public", attachment.Table, "ppm_partition_bounds").Return(nil).Once()
+		postgreSQLMock.On("SetPartitionReplicaIdentity", "public", attachment.Table, config.Table).Return(nil).Once()
+	}
+
+	postgreSQLMock.On("UnsetTableReadOnly", "public", original.Name).Return(nil).Once()
+	postgreSQLMock.On("DropTable", "public", original.Name).Return(nil).Once()
+
+	err := checker.SplitPartition("unittest", original.Name, partition.Daily, 100, false)
+	assert.Nil(t, err, "SplitPartition should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestSplitPartitionKeepsHeldPartition(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.Holds = []partition.Hold{{From: time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC), Reason: "Case 42"}}
+	original := twoDaysPartition(config)
+	original.Comment = `{"holdUntil":"2024-07-01T00:00:00Z","holdReason":"Case 43"}`
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Date(2024, 6, 20, 0, 0, 0, 0, time.UTC))
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{original}), nil).Once()
+	expectSplitCopy(postgreSQLMock, config, original, 200)
+
+	// The new partitions inherit the comment hold of the original partition
+	for _, name := range []string{"my_table_2024_06_13", "my_table_2024_06_14"} {
+		postgreSQLMock.On("GetTableComment", "public", name).Return("", nil).Once()
+		postgreSQLMock.On("SetTableComment", "public", name, `{"holdUntil":"2024-07-01T00:00:00Z","holdReason":"Case 43"}`).Return(nil).Once()
+	}
+
+	postgreSQLMock.On("ReplacePartitions", "public", config.Table, []string{original.Name}, mock.Anything).Return(nil).Once()
+	postgreSQLMock.On("DropConstraint", "public", mock.Anything, "ppm_partition_bounds").Return(nil).Twice()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", "public", mock.Anything, config.Table).Return(nil).Twice()
+	postgreSQLMock.On("UnsetTableReadOnly", "public", original.Name).Return(nil).Once()
+
+	// The original partition is held, it is not dropped
+
+	err := checker.SplitPartition("unittest", original.Name, partition.Daily, 100, false)
+	assert.Nil(t, err, "SplitPartition should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestSplitPartitionRowCountMismatch(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	original := twoDaysPartition(config)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{original}), nil).Once()
+	expectSplitCopy(postgreSQLMock, config, original, 201)
+
+	// The new tables are dropped and the partition is writable again
+	postgreSQLMock.On("IsTableExists", "public", "my_table_2024_06_13").Return(true, nil).Once()
+	postgreSQLMock.On("DropTable", "public", "my_table_2024_06_13").Return(nil).Once()
+	postgreSQLMock.On("IsTableExists", "public", "my_table_2024_06_14").Return(true, nil).Once()
+	postgreSQLMock.On("DropTable", "public", "my_table_2024_06_14").Return(nil).Once()
+	postgreSQLMock.On("UnsetTableReadOnly", "public", original.Name).Return(nil).Once()
+
+	err := checker.SplitPartition("unittest", original.Name, partition.Daily, 100, true)
+	assert.ErrorIs(t, err, ppm.ErrPartitionSplitFailed)
+	assert.ErrorIs(t, err, ppm.ErrRowCountMismatch)
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestSplitPartitionErrors(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	day, _ := config.GeneratePartition(time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC))
+	original := twoDaysPartition(config)
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{day, original}), nil)
+	postgreSQLMock.On("IsTableExists", "public", "my_table_2024_06_13").Return(true, nil).Once()
+
+	err := checker.SplitPartition("unittest", day.Name, partition.Daily, 100, false)
+	assert.ErrorIs(t, err, ppm.ErrInvalidSplit, "A daily partition can't be split into daily partitions")
+
+	err = checker.SplitPartition("unittest", original.Name, partition.Daily, 100, false)
+	assert.ErrorIs(t, err, ppm.ErrNameCollision, "New partitions must not exist")
+
+	err = checker.SplitPartition("unittest", "unknown", partition.Daily, 100, false)
+	assert.ErrorIs(t, err, ppm.ErrPartitionNotFound)
+
+	postgreSQLMock.AssertExpectations(t)
+}
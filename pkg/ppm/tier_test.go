@@ -0,0 +1,63 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionTiersKeepFirstPartitionOfPeriods(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	config.Tiers = []partition.RetentionTier{
+		{Every: partition.Monthly, Retention: 2},
+		{Every: partition.Yearly},
+	}
+
+	lastYear, _ := config.GeneratePartition(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	expiredMonth, _ := config.GeneratePartition(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	lastMonth, _ := config.GeneratePartition(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	expired, _ := config.GeneratePartition(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{lastYear, expiredMonth, lastMonth, expired, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	for _, p := range []partition.Partition{expiredMonth, expired} {
+		postgreSQLMock.On("DetachPartitionConcurrently", p.Schema, p.Name, p.ParentTable).Return(nil).Once()
+		postgreSQLMock.On("DropTable", p.Schema, p.Name).Return(nil).Once()
+	}
+
+	assert.Nil(t, checker.CleanupPartitions(), "Cleanup should keep partitions of the retention tiers")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestCheckAcceptsSparseRetentionTiers(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	config := OneDayPartitionConfiguration
+	config.Tiers = []partition.RetentionTier{{Every: partition.Monthly}}
+
+	lastMonth, _ := config.GeneratePartition(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{lastMonth, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+
+	assert.Nil(t, checker.CheckPartitions(), "Check should consider the partitions of retention tiers as intended")
+	postgreSQLMock.AssertExpectations(t)
+}
@@ -0,0 +1,177 @@
+package ppm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+)
+
+// truncatePartition empties a partition kept attached by the truncate cleanup policy.
+// Empty partitions are skipped, so cleanup does not lock them on every run.
+func (p PPM) truncatePartition(config partition.Configuration, part partition.Partition) error {
+	empty, err := p.db.IsTableEmpty(part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check if partition is empty: %w", err)
+	}
+
+	if empty {
+		p.logger.Debug("Partition is already empty, skip", "schema", part.Schema, "table", part.Name)
+
+		return nil
+	}
+
+	err = p.emptyPartition(config, part)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Info("Partition truncated", "schema", part.Schema, "table", part.Name, "parent_table", part.ParentTable)
+
+	return nil
+}
+
+// recyclePartition empties an expired partition and attaches it again with the bounds of the target partition.
+// It avoids the catalog churn of dropping and creating tables. The indexes created by PPM are renamed with the partition,
+// and the metadata recorded for the former range is removed.
+func (p PPM) recyclePartition(config partition.Configuration, expired, target partition.Partition) error {
+	err := p.emptyPartition(config, expired)
+	if err != nil {
+		return err
+	}
+
+	err = p.DetachPartition(expired)
+	if err != nil {
+		return err
+	}
+
+	err = p.renamePartition(config, expired, target.Name)
+	if err != nil {
+		return fmt.Errorf("failed to rename partition: %w", err)
+	}
+
+	if expired.Metadata != (partition.Metadata{}) {
+		target.Comment = expired.Comment
+
+		err = p.setMetadata(target)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = p.CreatePartition(config, target)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Info("Partition recycled", "schema", target.Schema, "table", target.Name, "previous_name", expired.Name)
+
+	return nil
+}
+
+// recycleExpiredPartitions recycles expired partitions into the missing expected partitions, before provisioning creates them.
+// Expired partitions go through the retention gate and the safeguards, as in cleanup.
+// It returns the found partitions, with the recycled partitions replaced by their new range.
+func (p PPM) recycleExpiredPartitions(config partition.Configuration, expected, foundPartitions []partition.Partition) ([]partition.Partition, error) {
+	targets := recycleTargets(config, expected, foundPartitions)
+	if len(targets) == 0 {
+		return foundPartitions, nil
+	}
+
+	keepIf, err := config.ParseKeepIf()
+	if err != nil {
+		return nil, fmt.Errorf("could not parse keepIf expression: %w", err)
+	}
+
+	expectedRange, err := p.getGlobalRange(expected)
+	if err != nil {
+		return nil, fmt.Errorf("could not evaluate expected range: %w", err)
+	}
+
+	candidates := p.withoutKeptPartitions(config, keepIf, foundPartitions, expectedRange)
+	expired, _ := splitExpiredPartitions(candidates, expectedRange)
+
+	expired, err = p.applyRetentionGate(config, expired)
+	if err != nil {
+		return nil, err
+	}
+
+	// Partitions allowed before a refusal are still recycled, as in cleanup
+	expired, err = p.applySafeguards(config, candidates, expired, false)
+	if err != nil && !errors.Is(err, ErrCleanupRefused) {
+		return nil, err
+	}
+
+	slices.SortFunc(expired, func(a, b partition.Partition) int {
+		return a.LowerBound.Compare(b.LowerBound)
+	})
+
+	recycled := slices.Clone(foundPartitions)
+
+	for i := 0; i < len(expired) && i < len(targets); i++ {
+		err = p.recyclePartition(config, expired[i], targets[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to recycle %s: %w", expired[i].Name, err)
+		}
+
+		recycled = slices.DeleteFunc(recycled, func(part partition.Partition) bool { return part.Name == expired[i].Name })
+		recycled = append(recycled, targets[i])
+	}
+
+	return recycled, nil
+}
+
+// emptyPartition truncates the partition, after removing the read-only protection that rejects TRUNCATE
+func (p PPM) emptyPartition(config partition.Configuration, part partition.Partition) error {
+	if config.ReadOnlyAfter > 0 {
+		err := p.db.UnsetTableReadOnly(part.Schema, part.Name)
+		if err != nil {
+			return fmt.Errorf("failed to unset partition read-only: %w", err)
+		}
+	}
+
+	err := p.db.TruncateTable(part.Schema, part.Name)
+	if err != nil {
+		return fmt.Errorf("failed to truncate partition: %w", err)
+	}
+
+	return nil
+}
+
+// recycleTargets returns the expected partitions after the newest existing partition, oldest first.
+// Provisioning and cleanup attach recycled partitions in their place, so no table is created for them.
+func recycleTargets(config partition.Configuration, expected, foundPartitions []partition.Partition) []partition.Partition {
+	if config.CleanupPolicy != partition.Truncate || !config.Recycle {
+		return nil
+	}
+
+	newest := newestUpperBound(foundPartitions)
+
+	var targets []partition.Partition
+
+	for _, part := range expected {
+		if !part.LowerBound.Before(newest) {
+			targets = append(targets, part)
+		}
+	}
+
+	slices.SortFunc(targets, func(a, b partition.Partition) int {
+		return a.LowerBound.Compare(b.LowerBound)
+	})
+
+	return targets
+}
+
+// splitExpiredPartitions separates the partitions entirely before the expected range from the other ones
+func splitExpiredPartitions(partitions []partition.Partition, expectedRange partition.PartitionRange) (expired, others []partition.Partition) {
+	for _, part := range partitions {
+		if !part.UpperBound.After(expectedRange.LowerBound) {
+			expired = append(expired, part)
+		} else {
+			others = append(others, part)
+		}
+	}
+
+	return expired, others
+}
@@ -0,0 +1,118 @@
+package ppm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qonto/postgresql-partition-manager/internal/infra/partition"
+	"github.com/qonto/postgresql-partition-manager/internal/infra/postgresql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupTruncatesPartitions(t *testing.T) {
+	config := OneDayPartitionConfiguration
+	config.CleanupPolicy = partition.Truncate
+
+	emptyPartition, _ := config.GeneratePartition(dayBeforeYesterday.AddDate(0, 0, -1))
+	expiredPartition, _ := config.GeneratePartition(dayBeforeYesterday)
+	yesterdayPartition, _ := config.GeneratePartition(yesterday)
+	currentPartition, _ := config.GeneratePartition(today)
+	tomorrowPartition, _ := config.GeneratePartition(tomorrow)
+	existing := []partition.Partition{emptyPartition, expiredPartition, yesterdayPartition, currentPartition, tomorrowPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, time.Now())
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil)
+	postgreSQLMock.On("IsTableEmpty", emptyPartition.Schema, emptyPartition.Name).Return(true, nil).Once()
+	postgreSQLMock.On("IsTableEmpty", expiredPartition.Schema, expiredPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("TruncateTable", expiredPartition.Schema, expiredPartition.Name).Return(nil).Once()
+
+	// Check considers truncated partitions as intended
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+
+	assert.Nil(t, checker.CleanupPartitions(), "CleanupPartitions should succeed")
+	assert.Nil(t, checker.CheckPartitions(), "Check should ignore truncated partitions")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestCleanupRecyclesPartitions(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.CleanupPolicy = partition.Truncate
+	config.Recycle = true
+	config.ReadOnlyAfter = 1
+
+	oldestPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -3))
+	oldestPartition.Metadata = partition.Metadata{HoldUntil: workDate.AddDate(0, 0, -1), HoldReason: "audit"}
+	oldestPartition.Comment = `{"holdUntil":"2024-06-14T00:00:00Z","holdReason":"audit","owner":"billing"}`
+	expiredPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -2))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+	existing := []partition.Partition{expiredPartition, oldestPartition, yesterdayPartition, currentPartition}
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, existing), nil).Once()
+
+	// The oldest partition is emptied, then attached again as the missing partition, with its indexes renamed and without its former metadata
+	postgreSQLMock.On("UnsetTableReadOnly", oldestPartition.Schema, oldestPartition.Name).Return(nil).Once()
+	postgreSQLMock.On("TruncateTable", oldestPartition.Schema, oldestPartition.Name).Return(nil).Once()
+	postgreSQLMock.On("DetachPartitionConcurrently", oldestPartition.Schema, oldestPartition.Name, config.Table).Return(nil).Once()
+	postgreSQLMock.On("ListPartitionedIndexes", config.Schema, config.Table).Return([]postgresql.PartitionedIndex{{Name: "created_at_idx"}}, nil).Once()
+	postgreSQLMock.On("ListTableIndexes", oldestPartition.Schema, oldestPartition.Name).Return([]postgresql.PartitionedIndex{{Name: "my_table_2024_06_12_created_at_idx"}}, nil).Once()
+	postgreSQLMock.On("RenameTableAndIndexes", oldestPartition.Schema, oldestPartition.Name, tomorrowPartition.Name,
+		[]postgresql.IndexRename{{Name: "my_table_2024_06_12_created_at_idx", NewName: "my_table_2024_06_16_created_at_idx"}}).Return(nil).Once()
+	postgreSQLMock.On("SetTableComment", config.Schema, tomorrowPartition.Name, `{"owner":"billing"}`).Return(nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("IsTableExists", config.Schema, tomorrowPartition.Name).Return(true, nil).Once()
+	postgreSQLMock.On("IsPartitionAttached", config.Schema, tomorrowPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("AttachPartition", config.Schema, tomorrowPartition.Name, config.Table, "2024-06-16", "2024-06-17").Return(nil).Once()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", config.Schema, tomorrowPartition.Name, config.Table).Return(nil).Once()
+
+	// No partition is missing anymore, the other expired partition is truncated
+	postgreSQLMock.On("IsTableEmpty", expiredPartition.Schema, expiredPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("UnsetTableReadOnly", expiredPartition.Schema, expiredPartition.Name).Return(nil).Once()
+	postgreSQLMock.On("TruncateTable", expiredPartition.Schema, expiredPartition.Name).Return(nil).Once()
+
+	assert.Nil(t, checker.CleanupPartitions(), "CleanupPartitions should succeed")
+	postgreSQLMock.AssertExpectations(t)
+}
+
+func TestRunAllRecyclesPartitions(t *testing.T) {
+	workDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	config := OneDayPartitionConfiguration
+	config.CleanupPolicy = partition.Truncate
+	config.Recycle = true
+
+	expiredPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -2))
+	yesterdayPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, -1))
+	currentPartition, _ := config.GeneratePartition(workDate)
+	tomorrowPartition, _ := config.GeneratePartition(workDate.AddDate(0, 0, 1))
+
+	checker, postgreSQLMock := setupPPM(t, config, workDate)
+
+	// Provisioning runs first, and recycles the expired partition as the missing partition
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{expiredPartition, yesterdayPartition, currentPartition}), nil).Once()
+	postgreSQLMock.On("TruncateTable", expiredPartition.Schema, expiredPartition.Name).Return(nil).Once()
+	postgreSQLMock.On("DetachPartitionConcurrently", expiredPartition.Schema, expiredPartition.Name, config.Table).Return(nil).Once()
+	postgreSQLMock.On("ListPartitionedIndexes", config.Schema, config.Table).Return([]postgresql.PartitionedIndex{}, nil).Once()
+	postgreSQLMock.On("ListTableIndexes", expiredPartition.Schema, expiredPartition.Name).Return([]postgresql.PartitionedIndex{}, nil).Once()
+	postgreSQLMock.On("RenameTableAndIndexes", expiredPartition.Schema, expiredPartition.Name, tomorrowPartition.Name, []postgresql.IndexRename(nil)).Return(nil).Once()
+	postgreSQLMock.On("GetPartitionSettings", config.Schema, config.Table).Return(string(partition.Range), config.PartitionKey, nil).Once()
+	postgreSQLMock.On("GetColumnDataType", config.Schema, config.Table, config.PartitionKey).Return(postgresql.Date, nil).Once()
+	postgreSQLMock.On("IsTableExists", config.Schema, tomorrowPartition.Name).Return(true, nil).Once()
+	postgreSQLMock.On("IsPartitionAttached", config.Schema, tomorrowPartition.Name).Return(false, nil).Once()
+	postgreSQLMock.On("AttachPartition", config.Schema, tomorrowPartition.Name, config.Table, "2024-06-16", "2024-06-17").Return(nil).Once()
+	postgreSQLMock.On("SetPartitionReplicaIdentity", config.Schema, tomorrowPartition.Name, config.Table).Return(nil).Once()
+
+	// Cleanup then finds the expected partitions
+	postgreSQLMock.On("ListPartitions", config.Schema, config.Table).Return(partitionResultToPartition(t, []partition.Partition{yesterdayPartition, currentPartition, tomorrowPartition}), nil).Once()
+
+	assert.Nil(t, checker.ProvisioningPartitions(), "ProvisioningPartitions should succeed")
+	assert.Nil(t, checker.CleanupPartitions(), "CleanupPartitions should succeed")
+	postgreSQLMock.AssertExpectations(t)
+	postgreSQLMock.AssertNotCalled(t, "CreateTableLikeTable", config.Schema, tomorrowPartition.Name, config.Table)
+}